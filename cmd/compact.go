@@ -0,0 +1,35 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+func runCompact(args []string) error {
+	fs := flag.NewFlagSet("compact", flag.ContinueOnError)
+	popPath := fs.String("population", "population.json", "population `file` to compact")
+	out := fs.String("o", "compacted.json", "output population `file`")
+	minExp := fs.Int64("min-exp", -1, "drop rules with less experience than this (-1 for the thetaSub of the population)")
+	maxError := fs.Float64("max-error", -1, "drop rules with a prediction error of at least this value (-1 for the errorZero of the population)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	algorithm, params, ruleSet, err := xcs.LoadFileWithAlgorithm(*popPath)
+	if err != nil {
+		return err
+	}
+	if *minExp < 0 {
+		*minExp = int64(params.ThetaSub)
+	}
+	if *maxError < 0 {
+		*maxError = params.ErrorZero
+	}
+	compacted := xcs.Compact(ruleSet, *minExp, *maxError)
+	if err := xcs.SaveFileWithAlgorithm(*out, algorithm, params, compacted); err != nil {
+		return err
+	}
+	fmt.Printf("Compacted %v classifiers to %v; wrote %v\n", ruleSet.Len(), compacted.Len(), *out)
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/acs2"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/config"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/ucs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/zcs"
+)
+
+// assessor is what eval needs of an algorithm; Xcs, Ucs and Zcs provide
+// it.
+type assessor interface {
+	SetSchedule(schedule xcs.Schedule) error
+	Assess(problem mli.Problem, ruleSet *xcs.Population, samples int) (xcs.Assessment, error)
+}
+
+func runEval(args []string) error {
+	fs := flag.NewFlagSet("eval", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	popPath := fs.String("population", "population.json", "population `file` to evaluate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+	if cfg.Algorithm == "acs2" {
+		return evalAcs2(cfg, *popPath)
+	}
+	algorithm, params, ruleSet, err := xcs.LoadFileWithAlgorithm(*popPath)
+	if err != nil {
+		return err
+	}
+	if err := checkAlgorithm(*popPath, algorithm, cfg); err != nil {
+		return err
+	}
+	rnd := cfg.NewRand()
+	problem, err := cfg.NewProblem(rnd)
+	if err != nil {
+		return err
+	}
+	var alg assessor
+	switch cfg.Algorithm {
+	case "ucs":
+		alg, err = ucs.New(params, rnd)
+	case "zcs":
+		alg, err = zcs.New(params, cfg.Zcs, rnd)
+	default:
+		alg, err = xcs.New(params, rnd)
+	}
+	if err != nil {
+		return err
+	}
+	if err := alg.SetSchedule(cfg.Run); err != nil {
+		return err
+	}
+	a, err := alg.Assess(problem, ruleSet, cfg.Run.EvalSamples)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v over %v episodes: %v\n", a.Metric.Description(), cfg.Run.EvalSamples, a.Value)
+	return nil
+}
+
+// evalAcs2 evaluates an ACS2 population, which is not that of package
+// xcs.
+func evalAcs2(cfg config.Config, popPath string) error {
+	params, ruleSet, err := acs2.LoadFile(popPath)
+	if err != nil {
+		return err
+	}
+	rnd := cfg.NewRand()
+	problem, err := cfg.NewProblem(rnd)
+	if err != nil {
+		return err
+	}
+	alg, err := acs2.New(params, rnd)
+	if err != nil {
+		return err
+	}
+	if err := alg.SetSchedule(cfg.Run); err != nil {
+		return err
+	}
+	a, err := alg.Assess(problem, ruleSet, cfg.Run.EvalSamples)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v over %v episodes: %v\n", a.Metric.Description(), cfg.Run.EvalSamples, a.Value)
+	return nil
+}
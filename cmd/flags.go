@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/config"
+)
+
+// settingList collects repeated -set key=value flags.
+type settingList []string
+
+func (s *settingList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *settingList) Set(v string) error {
+	if !strings.Contains(v, "=") {
+		return fmt.Errorf("expected key=value, got %q", v)
+	}
+	*s = append(*s, v)
+	return nil
+}
+
+// shortcuts maps convenience flags onto configuration keys.
+var shortcuts = map[string]string{
+	"seed":          "seed",
+	"problem":       "problem.type",
+	"size":          "problem.size",
+	"iterations":    "run.iterations",
+	"eval-interval": "run.evalInterval",
+	"eval-samples":  "run.evalSamples",
+
+	"checkpoint-interval": "run.checkpointInterval",
+}
+
+type configFlags struct {
+	path     string
+	settings settingList
+}
+
+// addConfigFlags registers the flags shared by commands that need a
+// configuration. Values given on the command line override those read
+// from the configuration file.
+func addConfigFlags(fs *flag.FlagSet) *configFlags {
+	cf := &configFlags{}
+	fs.StringVar(&cf.path, "config", "", "JSON, YAML or TOML configuration `file`")
+	fs.Var(&cf.settings, "set", "override a configuration `key=value`, e.g. xcs.maxPop=800 (repeatable)")
+	fs.Int64("seed", 0, "random seed, 0 for one taken from the clock (seed)")
+	fs.String("problem", "", "problem type (problem.type)")
+	fs.Int("size", 0, "problem size (problem.size)")
+	fs.Int("iterations", 0, "training iterations (run.iterations)")
+	fs.Int("eval-interval", 0, "iterations between evaluations, 0 to disable (run.evalInterval)")
+	fs.Int("eval-samples", 0, "episodes per evaluation (run.evalSamples)")
+	fs.Int("checkpoint-interval", 0, "iterations between checkpoints, 0 to disable (run.checkpointInterval)")
+	return cf
+}
+
+// checkAlgorithm rejects a population evolved by an algorithm other than
+// the configured one, which would score it with the wrong prediction.
+func checkAlgorithm(path, algorithm string, cfg config.Config) error {
+	if algorithm != cfg.Algorithm {
+		return fmt.Errorf("%v was evolved by %v, not the configured %v", path, algorithm, cfg.Algorithm)
+	}
+	return nil
+}
+
+func (cf *configFlags) load(fs *flag.FlagSet) (config.Config, error) {
+	cfg := config.Default()
+	if cf.path != "" {
+		var err error
+		if cfg, err = config.Load(cf.path); err != nil {
+			return config.Config{}, err
+		}
+	}
+	for _, s := range cf.settings {
+		kv := strings.SplitN(s, "=", 2)
+		if err := cfg.Set(kv[0], kv[1]); err != nil {
+			return config.Config{}, err
+		}
+	}
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		key, ok := shortcuts[f.Name]
+		if ok && err == nil {
+			err = cfg.Set(key, f.Value.String())
+		}
+	})
+	if err != nil {
+		return config.Config{}, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return config.Config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+var sortKeys = map[string]func(a, b *xcs.Classifier) bool{
+	"numerosity": func(a, b *xcs.Classifier) bool { return a.GetNumerosity() > b.GetNumerosity() },
+	"fitness":    func(a, b *xcs.Classifier) bool { return a.GetFitness() > b.GetFitness() },
+	"error":      func(a, b *xcs.Classifier) bool { return a.GetPredictionError() < b.GetPredictionError() },
+	"experience": func(a, b *xcs.Classifier) bool { return a.GetExperience() > b.GetExperience() },
+	"generality": func(a, b *xcs.Classifier) bool { return a.GetGenerality() > b.GetGenerality() },
+	"payoff":     func(a, b *xcs.Classifier) bool { return a.GetPayoff() > b.GetPayoff() },
+}
+
+func runInspect(args []string) error {
+	fs := flag.NewFlagSet("inspect", flag.ContinueOnError)
+	popPath := fs.String("population", "population.json", "population `file` to inspect")
+	sortBy := fs.String("sort", "numerosity", "order rules by numerosity, fitness, error, experience, generality or payoff")
+	action := fs.Int("action", -1, "only show rules advocating this action (-1 for all)")
+	minNum := fs.Int("min-numerosity", 1, "only show rules with at least this numerosity")
+	maxError := fs.Float64("max-error", -1, "only show rules with a prediction error below this value (-1 for all)")
+	limit := fs.Int("limit", 0, "show at most this many rules (0 for all)")
+	statsOnly := fs.Bool("stats", false, "print the statistics only")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	less, ok := sortKeys[*sortBy]
+	if !ok {
+		return fmt.Errorf("unknown sort key %q", *sortBy)
+	}
+	_, _, ruleSet, err := xcs.LoadFileWithAlgorithm(*popPath)
+	if err != nil {
+		return err
+	}
+
+	s := xcs.Summarize(ruleSet)
+	fmt.Printf("Macro-classifiers:   %v\n", s.MacroClassifiers)
+	fmt.Printf("Micro-classifiers:   %v\n", s.MicroClassifiers)
+	fmt.Printf("Average fitness:     %.4f\n", s.AverageFitness)
+	fmt.Printf("Average error:       %.4f\n", s.AverageError)
+	fmt.Printf("Average experience:  %.1f\n", s.AverageExp)
+	fmt.Printf("Average generality:  %.4f\n", s.AverageGenerality)
+	actions := make([]int, 0, len(s.MacroPerAction))
+	for a := range s.MacroPerAction {
+		actions = append(actions, a)
+	}
+	sort.Ints(actions)
+	for _, a := range actions {
+		fmt.Printf("Rules for action %v:  %v\n", a, s.MacroPerAction[a])
+	}
+	if *statsOnly {
+		return nil
+	}
+
+	var rules []*xcs.Classifier
+	for _, cl := range ruleSet.Classifiers() {
+		if *action >= 0 && cl.GetAction() != *action {
+			continue
+		}
+		if cl.GetNumerosity() < int32(*minNum) {
+			continue
+		}
+		if *maxError >= 0 && cl.GetPredictionError() >= *maxError {
+			continue
+		}
+		rules = append(rules, cl)
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return less(rules[i], rules[j]) })
+	if *limit > 0 && len(rules) > *limit {
+		rules = rules[:*limit]
+	}
+	fmt.Println()
+	for _, cl := range rules {
+		fmt.Printf("%v [NUM: %v; FIT: %.4f; EXP: %v]\n", cl.ToString(), cl.GetNumerosity(), cl.GetFitness(), cl.GetExperience())
+	}
+	return nil
+}
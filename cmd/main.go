@@ -1,14 +1,61 @@
-// Package main runs the XCS algorithm on the 6-bit Boolean multiplexer
-// problem.
+// Package main provides the xcs command, which trains, evaluates,
+// inspects and compacts XCS classifier populations.
 package main
 
 import (
-	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
-	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"train", "train a population and write it to a file", runTrain},
+	{"eval", "score a saved population on a problem", runEval},
+	{"predict", "print the action a saved population chooses for inputs", runPredict},
+	{"inspect", "print statistics and rules of a saved population", runInspect},
+	{"compact", "remove inexperienced, inaccurate and subsumed rules", runCompact},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: %v <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8v %v\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun '%v <command> -h' for the flags of a command.\n", os.Args[0])
+}
+
 func main() {
-	prob := multiplexer.New(6)
-	alg := &xcs.Xcs{}
-	alg.OperateOn(prob)
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		err := c.run(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if name == "-h" || name == "-help" || name == "help" {
+		usage()
+		return
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
 }
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/ucs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/zcs"
+)
+
+func runPredict(args []string) error {
+	fs := flag.NewFlagSet("predict", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	popPath := fs.String("population", "population.json", "population `file` to query")
+	explain := fs.Bool("explain", false, "list the matching rules behind each prediction")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: predict [flags] inputs...\n\n"+
+			"Each input is a string of digits (e.g. 010110) or a comma-separated\nlist of integers (e.g. 0,12,3). Populations with real-valued conditions\ntake a comma-separated list of numbers (e.g. 0.2,0.75,0.5).\n\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("no inputs given")
+	}
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+	model, err := xcs.LoadModel(*popPath)
+	if err != nil {
+		return err
+	}
+	if err := checkAlgorithm(*popPath, model.GetAlgorithm(), cfg); err != nil {
+		return err
+	}
+	// UCS and ZCS combine the advocates of an action as they do in
+	// training; see cmd/eval.go.
+	switch cfg.Algorithm {
+	case "ucs":
+		model.SetPredictionFunc(ucs.Vote)
+	case "zcs":
+		model.SetPredictionFunc(zcs.Strengths)
+	case "acs2":
+		return fmt.Errorf("predict does not support algorithm %q", cfg.Algorithm)
+	}
+	realValued := model.GetParams().Condition == xcs.ConditionReal
+	for _, arg := range fs.Args() {
+		var ex xcs.Explanation
+		var pa map[int]float64
+		var action int
+		if realValued {
+			inputs, err := parseRealInputs(arg)
+			if err != nil {
+				return err
+			}
+			if *explain {
+				ex, err = model.ExplainReal(inputs)
+			} else if pa, err = model.PredictionArrayReal(inputs); err == nil {
+				action, err = model.PredictReal(inputs)
+			}
+		} else {
+			inputs, err := parseInputs(arg)
+			if err != nil {
+				return err
+			}
+			if *explain {
+				ex, err = model.Explain(inputs)
+			} else if pa, err = model.PredictionArray(inputs); err == nil {
+				action, err = model.Predict(inputs)
+			}
+		}
+		switch {
+		case errors.Is(err, xcs.ErrNoMatch):
+			fmt.Printf("%v --> no matching classifier\n", arg)
+		case err != nil:
+			return err
+		case *explain:
+			fmt.Print(ex)
+		default:
+			fmt.Printf("%v --> %v %v\n", arg, action, formatPredictionArray(pa))
+		}
+	}
+	return nil
+}
+
+func parseInputs(arg string) ([]int, error) {
+	var fields []string
+	if strings.Contains(arg, ",") {
+		fields = strings.Split(arg, ",")
+	} else {
+		fields = strings.Split(arg, "")
+	}
+	inputs := make([]int, len(fields))
+	for i, f := range fields {
+		v, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %q: %w", arg, err)
+		}
+		inputs[i] = v
+	}
+	return inputs, nil
+}
+
+func parseRealInputs(arg string) ([]float64, error) {
+	fields := strings.Split(arg, ",")
+	inputs := make([]float64, len(fields))
+	for i, f := range fields {
+		v, err := strconv.ParseFloat(strings.TrimSpace(f), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid input %q: %w", arg, err)
+		}
+		inputs[i] = v
+	}
+	return inputs, nil
+}
+
+func formatPredictionArray(pa map[int]float64) string {
+	actions := make([]int, 0, len(pa))
+	for a := range pa {
+		actions = append(actions, a)
+	}
+	sort.Ints(actions)
+	parts := make([]string, len(actions))
+	for i, a := range actions {
+		parts[i] = fmt.Sprintf("%v: %.2f", a, pa[a])
+	}
+	return "[" + strings.Join(parts, "; ") + "]"
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/acs2"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/config"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// learner is what train needs of an algorithm; Xcs, Ucs and Zcs provide
+// it.
+type learner interface {
+	SetObserver(observer xcs.Observer)
+	Train(problem mli.Problem) (*xcs.Population, error)
+	GetParams() xcs.Params
+}
+
+func runTrain(args []string) (err error) {
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	cf := addConfigFlags(fs)
+	out := fs.String("o", "population.json", "output population `file` (JSON if it ends in .json, binary otherwise)")
+	checkpointPath := fs.String("checkpoint", "", "write checkpoints to this `file` (see -checkpoint-interval)")
+	resumePath := fs.String("resume", "", "resume the run saved in this checkpoint `file`")
+	printPop := fs.Bool("print", false, "print the evolved population when training finishes")
+	metricsPath := fs.String("metrics", "", "write a learning curve to this `file` (JSON Lines if it ends in .jsonl or .ndjson, CSV otherwise)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := cf.load(fs)
+	if err != nil {
+		return err
+	}
+	rnd := cfg.NewRand()
+	problem, err := cfg.NewProblem(rnd)
+	if err != nil {
+		return err
+	}
+	if cfg.Algorithm != "xcs" && (*checkpointPath != "" || *resumePath != "") {
+		return fmt.Errorf("checkpoints are not supported for algorithm %q", cfg.Algorithm)
+	}
+	console := &xcs.ConsoleObserver{Writer: os.Stdout, PrintPopulation: *printPop}
+	var observer xcs.Observer = console
+	if *metricsPath != "" {
+		f, err := os.Create(*metricsPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		metrics := xcs.NewMetricsWriter(f, xcs.MetricsFormatForPath(*metricsPath))
+		defer func() {
+			if ferr := metrics.Flush(); ferr != nil && err == nil {
+				err = ferr
+			}
+		}()
+		observer = xcs.Observers{console, metrics}
+	}
+	if cfg.Algorithm == "acs2" {
+		return trainAcs2(cfg, rnd, problem, observer, *out, *printPop)
+	}
+	var alg learner
+	var x *xcs.Xcs
+	switch cfg.Algorithm {
+	case "ucs":
+		if alg, err = cfg.NewUcs(rnd); err != nil {
+			return err
+		}
+	case "zcs":
+		if alg, err = cfg.NewZcs(rnd); err != nil {
+			return err
+		}
+	default:
+		if x, err = cfg.NewXcs(rnd); err != nil {
+			return err
+		}
+		alg = x
+	}
+	alg.SetObserver(observer)
+	if *checkpointPath != "" {
+		x.SetCheckpointHandler(func(cp *xcs.Checkpoint) error {
+			return xcs.SaveCheckpointFile(*checkpointPath, cp)
+		})
+	}
+	var ruleSet *xcs.Population
+	if *resumePath != "" {
+		cp, err := xcs.LoadCheckpointFile(*resumePath)
+		if err != nil {
+			return err
+		}
+		if ruleSet, err = x.Resume(problem, cp); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("Seed: %v\n", cfg.Seed)
+		if ruleSet, err = alg.Train(problem); err != nil {
+			return err
+		}
+	}
+	if err := xcs.SaveFileWithAlgorithm(*out, cfg.Algorithm, alg.GetParams(), ruleSet); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %v classifiers to %v\n", ruleSet.Len(), *out)
+	return nil
+}
+
+// trainAcs2 trains ACS2, whose population is not that of package xcs.
+func trainAcs2(cfg config.Config, rnd *rng.Rand, problem mli.Problem, observer xcs.Observer, out string, printPop bool) error {
+	alg, err := cfg.NewAcs2(rnd)
+	if err != nil {
+		return err
+	}
+	alg.SetObserver(observer)
+	fmt.Printf("Seed: %v\n", cfg.Seed)
+	ruleSet, err := alg.Train(problem)
+	if err != nil {
+		return err
+	}
+	if printPop {
+		for _, cl := range ruleSet.Classifiers() {
+			fmt.Println(cl.ToString())
+		}
+	}
+	if err := acs2.SaveFile(out, alg.GetParams(), ruleSet); err != nil {
+		return err
+	}
+	fmt.Printf("Wrote %v classifiers to %v\n", ruleSet.Len(), out)
+	return nil
+}
@@ -0,0 +1,687 @@
+// Package acs2 provides the anticipatory classifier system ACS2 (Butz &
+// Stolzmann, 2002). Its classifiers anticipate the effect of an action as
+// well as its reward, so that an ACS2 run learns a model of its
+// environment alongside a policy. The anticipatory learning process
+// specializes classifiers on the attributes that change and on those
+// that distinguish the states in which an anticipation failed, recorded
+// in the classifier's mark; reinforcement learning updates the reward
+// predictions; and an optional genetic algorithm generalizes
+// over-specialized classifiers.
+//
+// ACS2 learns from the change an action makes to the observed state, so
+// it requires an mli.ObservableProblem with discrete inputs, such as a
+// maze. Every input is an attribute that may take any integer value.
+package acs2
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// ErrNotObservable is returned for problems that do not implement
+// mli.ObservableProblem.
+var ErrNotObservable = errors.New("problem does not expose its state")
+
+type Acs2 struct {
+	params   Params
+	schedule xcs.Schedule
+	rnd      *rng.Rand
+	observer xcs.Observer
+}
+
+// New returns an ACS2 that draws all of its random numbers from rnd.
+func New(params Params, rnd *rng.Rand) (*Acs2, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if rnd == nil {
+		return nil, errors.New("a random number generator is required")
+	}
+	a := &Acs2{params: params, schedule: xcs.DefaultSchedule(), rnd: rnd}
+	a.SetObserver(&xcs.ConsoleObserver{Writer: os.Stdout})
+	return a, nil
+}
+
+// SetObserver replaces the observer notified of run events; nil silences
+// the run. ACS2 only reports the end of each iteration, of each
+// evaluation and of the run, the last without a population, as its
+// classifiers are not those of package xcs.
+func (a *Acs2) SetObserver(observer xcs.Observer) {
+	if observer == nil {
+		observer = xcs.NopObserver{}
+	}
+	a.observer = observer
+}
+
+func (a *Acs2) GetParams() Params {
+	return a.params
+}
+
+// SetSchedule sets the schedule of training and evaluation. ACS2 does not
+// predict payoffs, so the systemError metric is rejected.
+func (a *Acs2) SetSchedule(schedule xcs.Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
+	}
+	if schedule.Metric == mli.MetricSystemError {
+		return xcs.InvalidParam("metric", schedule.Metric, "is not measured by ACS2")
+	}
+	a.schedule = schedule
+	return nil
+}
+
+// OperateOn trains on the problem, reporting progress to the observer.
+// As mli.Algorithm gives it no way to return an error, a failure is
+// logged and ends the run; use Train to handle errors.
+func (a *Acs2) OperateOn(problem mli.Problem) {
+	if _, err := a.Train(problem); err != nil {
+		log.Print(err)
+	}
+}
+
+// Train runs the configured number of episodes on the problem, each an
+// explore trial, and returns the evolved population.
+func (a *Acs2) Train(problem mli.Problem) (*Population, error) {
+	observable, ok := problem.(mli.ObservableProblem)
+	if !ok {
+		return nil, ErrNotObservable
+	}
+	ruleSet := NewPopulation()
+	step := int64(0)
+	for i := 0; i < a.schedule.Iterations; i++ {
+		steps, reward := a.runEpisode(observable, ruleSet, &step)
+		a.observer.IterationFinished(xcs.IterationEvent{Iteration: i, Steps: steps, TotalReward: reward, MacroClassifiers: ruleSet.Len()})
+		if a.schedule.EvalInterval > 0 && i != 0 && i%a.schedule.EvalInterval == 0 {
+			if err := a.Evaluate(problem, ruleSet, i); err != nil {
+				return nil, fmt.Errorf("evaluation after iteration %v: %w", i, err)
+			}
+		}
+	}
+	a.observer.TrainingFinished(xcs.TrainingEvent{Iterations: a.schedule.Iterations})
+	return ruleSet, nil
+}
+
+func (a *Acs2) runEpisode(problem mli.ObservableProblem, ruleSet *Population, step *int64) (int, float64) {
+	problem.Reset()
+	state := problem.ObtainInput().GetInputs()
+	var previous []int
+	var lastActionSet []*Classifier
+	lastAction := -1
+	lastReward := 0.0
+	steps := 0
+	totalReward := 0.0
+	for !problem.IsAtEndState() {
+		if lastAction >= 0 {
+			lastActionSet = a.ApplyAlp(lastActionSet, lastAction, previous, state, ruleSet, *step)
+		}
+		matchSet := a.matchSet(ruleSet, state)
+		if lastAction >= 0 {
+			a.ApplyReinforcementLearning(lastActionSet, lastReward, a.maxPrediction(matchSet))
+			a.ApplyGeneticAlgorithm(lastActionSet, ruleSet, *step)
+		}
+		action := a.selectAction(matchSet)
+		actionSet := actionSet(matchSet, action)
+		lastReward = float64(problem.Effect(action))
+		previous, state = state, problem.Observe().GetInputs()
+		totalReward += lastReward
+		steps++
+		*step++
+		if problem.IsAtEndState() {
+			actionSet = a.ApplyAlp(actionSet, action, previous, state, ruleSet, *step)
+			a.ApplyReinforcementLearning(actionSet, lastReward, 0)
+			a.ApplyGeneticAlgorithm(actionSet, ruleSet, *step)
+		}
+		lastActionSet, lastAction = actionSet, action
+	}
+	return steps, totalReward
+}
+
+func (a *Acs2) matchSet(ruleSet *Population, state []int) []*Classifier {
+	var matchSet []*Classifier
+	for _, cl := range ruleSet.Classifiers() {
+		if cl.Matches(state) {
+			matchSet = append(matchSet, cl)
+		}
+	}
+	return matchSet
+}
+
+func actionSet(matchSet []*Classifier, action int) []*Classifier {
+	var set []*Classifier
+	for _, cl := range matchSet {
+		if cl.Action == action {
+			set = append(set, cl)
+		}
+	}
+	return set
+}
+
+// selectAction chooses a random action with probability Epsilon and the
+// best action otherwise.
+func (a *Acs2) selectAction(matchSet []*Classifier) int {
+	if a.rnd.Float64() < a.params.Epsilon {
+		return a.rnd.Intn(a.params.MaxAction + 1)
+	}
+	return a.bestAction(matchSet)
+}
+
+// bestAction returns the action of the classifier with the highest
+// product of quality and reward, considering only classifiers that
+// anticipate a change if there are any, or a random action for an empty
+// match set.
+func (a *Acs2) bestAction(matchSet []*Classifier) int {
+	candidates := changing(matchSet)
+	if len(candidates) == 0 {
+		return a.rnd.Intn(a.params.MaxAction + 1)
+	}
+	best := candidates[0]
+	for _, cl := range candidates[1:] {
+		if cl.Quality*cl.Reward > best.Quality*best.Reward {
+			best = cl
+		}
+	}
+	return best.Action
+}
+
+// maxPrediction returns the highest product of quality and reward in the
+// match set, over the same classifiers as bestAction.
+func (a *Acs2) maxPrediction(matchSet []*Classifier) float64 {
+	maxP := 0.0
+	for _, cl := range changing(matchSet) {
+		maxP = math.Max(maxP, cl.Quality*cl.Reward)
+	}
+	return maxP
+}
+
+// changing returns the classifiers of the match set that anticipate a
+// change, or the whole match set if none does, as in problems whose
+// actions never change the state.
+func changing(matchSet []*Classifier) []*Classifier {
+	var set []*Classifier
+	for _, cl := range matchSet {
+		if cl.AnticipatesChange() {
+			set = append(set, cl)
+		}
+	}
+	if len(set) == 0 {
+		return matchSet
+	}
+	return set
+}
+
+// ApplyAlp applies the anticipatory learning process to the action set
+// of action, which acted in previous and led to next, and returns the
+// action set without the classifiers it removed and with those it added.
+// If no member anticipated correctly, a classifier covering the change
+// is added.
+func (a *Acs2) ApplyAlp(actionSet []*Classifier, action int, previous, next []int, ruleSet *Population, step int64) []*Classifier {
+	expectedCase := false
+	result := append([]*Classifier(nil), actionSet...)
+	for _, cl := range actionSet {
+		cl.Exp++
+		a.updateApplicationAverage(cl, step)
+		var child *Classifier
+		if cl.Anticipates(previous, next) {
+			child = a.expectedCase(cl, previous)
+			expectedCase = true
+		} else {
+			child = a.unexpectedCase(cl, previous, next)
+			if cl.Quality < a.params.ThetaI {
+				ruleSet.Remove(cl)
+				result = remove(result, cl)
+			}
+		}
+		if child != nil {
+			child.TimeStamp = step
+			result = a.addAlpClassifier(child, result, ruleSet)
+		}
+	}
+	if !expectedCase {
+		result = a.addAlpClassifier(coverTriple(previous, action, next, step), result, ruleSet)
+	}
+	return result
+}
+
+func remove(set []*Classifier, cl *Classifier) []*Classifier {
+	for i, member := range set {
+		if member == cl {
+			return append(set[:i:i], set[i+1:]...)
+		}
+	}
+	return set
+}
+
+func (a *Acs2) updateApplicationAverage(cl *Classifier, step int64) {
+	elapsed := float64(step - cl.AlpTimeStamp)
+	if float64(cl.Exp) < 1/a.params.Beta {
+		cl.ApplicationAverage += (elapsed - cl.ApplicationAverage) / float64(cl.Exp)
+	} else {
+		cl.ApplicationAverage += a.params.Beta * (elapsed - cl.ApplicationAverage)
+	}
+	cl.AlpTimeStamp = step
+}
+
+// expectedCase raises the quality of a classifier that anticipated
+// correctly. If it is marked and state differs from its mark, it also
+// returns a child specialized on the differences, so that the states in
+// which it succeeds are told apart from those in which it failed.
+func (a *Acs2) expectedCase(cl *Classifier, state []int) *Classifier {
+	diff := a.differences(cl, state)
+	if diff == nil {
+		cl.Quality += a.params.Beta * (1 - cl.Quality)
+		return nil
+	}
+	child := cl.copy()
+	if a.params.UMax > 0 {
+		a.limitSpecificity(child, diff)
+	}
+	for i, v := range diff {
+		if v != DontCare {
+			child.Condition[i] = v
+		}
+	}
+	child.Quality = math.Max(child.Quality, 0.5)
+	child.Exp = 1
+	return child
+}
+
+// differences returns the attributes on which state differs from the
+// mark of the classifier: one at random of those whose value the mark
+// has never seen, or else all of those whose mark holds several values.
+// It returns nil for an unmarked classifier or if there are none.
+func (a *Acs2) differences(cl *Classifier, state []int) []int {
+	if !cl.IsMarked() {
+		return nil
+	}
+	var unique, ambiguous []int
+	for i, v := range cl.Condition {
+		if v != DontCare || len(cl.Mark[i]) == 0 {
+			continue
+		}
+		if !containsInt(cl.Mark[i], state[i]) {
+			unique = append(unique, i)
+		} else if len(cl.Mark[i]) > 1 {
+			ambiguous = append(ambiguous, i)
+		}
+	}
+	if len(unique) == 0 && len(ambiguous) == 0 {
+		return nil
+	}
+	diff := make([]int, len(state))
+	for i := range diff {
+		diff[i] = DontCare
+	}
+	if len(unique) > 0 {
+		i := unique[a.rnd.Intn(len(unique))]
+		diff[i] = state[i]
+	} else {
+		for _, i := range ambiguous {
+			diff[i] = state[i]
+		}
+	}
+	return diff
+}
+
+// limitSpecificity generalizes the condition of child or drops
+// attributes from diff until specifying diff would leave at most UMax
+// attributes specified.
+func (a *Acs2) limitSpecificity(child *Classifier, diff []int) {
+	spec := child.Specificity()
+	specNew := 0
+	for _, v := range diff {
+		if v != DontCare {
+			specNew++
+		}
+	}
+	for spec+specNew > a.params.UMax {
+		if spec > 0 && (specNew == 0 || a.rnd.Float64() < 0.5) {
+			generalizeRandom(child.Condition, a.rnd)
+			spec--
+		} else {
+			generalizeRandom(diff, a.rnd)
+			specNew--
+		}
+	}
+}
+
+// generalizeRandom sets a randomly chosen specified attribute to
+// DontCare.
+func generalizeRandom(values []int, rnd rng.Generator) {
+	var specified []int
+	for i, v := range values {
+		if v != DontCare {
+			specified = append(specified, i)
+		}
+	}
+	if len(specified) > 0 {
+		values[specified[rnd.Intn(len(specified))]] = DontCare
+	}
+}
+
+// unexpectedCase lowers the quality of a classifier that anticipated
+// wrongly and marks it with the previous state. If the attributes its
+// effect specifies did change as anticipated, it also returns a child
+// that anticipates the other changes too.
+func (a *Acs2) unexpectedCase(cl *Classifier, previous, next []int) *Classifier {
+	cl.Quality -= a.params.Beta * cl.Quality
+	cl.SetMark(previous)
+	for i, v := range cl.Effect {
+		if v != DontCare && (v != next[i] || previous[i] == next[i]) {
+			return nil
+		}
+	}
+	child := cl.copy()
+	for i, v := range cl.Effect {
+		if v == DontCare && previous[i] != next[i] {
+			child.Condition[i] = previous[i]
+			child.Effect[i] = next[i]
+		}
+	}
+	child.Quality = math.Max(child.Quality, 0.5)
+	child.Exp = 1
+	return child
+}
+
+// coverTriple returns a classifier anticipating exactly the change from
+// previous to next.
+func coverTriple(previous []int, action int, next []int, step int64) *Classifier {
+	cl := newClassifier(len(previous), action, step)
+	for i := range previous {
+		if previous[i] != next[i] {
+			cl.Condition[i] = previous[i]
+			cl.Effect[i] = next[i]
+		}
+	}
+	return cl
+}
+
+// addAlpClassifier adds a classifier generated by the anticipatory
+// learning process unless a member of the action set subsumes or equals
+// it, in which case that member's quality is raised instead.
+func (a *Acs2) addAlpClassifier(cl *Classifier, actionSet []*Classifier, ruleSet *Population) []*Classifier {
+	if old := a.findSubsumerOrEqual(cl, actionSet); old != nil {
+		old.Quality += a.params.Beta * (1 - old.Quality)
+		return actionSet
+	}
+	ruleSet.Add(cl)
+	return append(actionSet, cl)
+}
+
+// findSubsumerOrEqual returns the most general member of the set that
+// subsumes cl, or else a member equal to it, or nil.
+func (a *Acs2) findSubsumerOrEqual(cl *Classifier, set []*Classifier) *Classifier {
+	var subsumer *Classifier
+	for _, member := range set {
+		if a.subsumes(member, cl) && (subsumer == nil || member.Specificity() < subsumer.Specificity()) {
+			subsumer = member
+		}
+	}
+	if subsumer != nil {
+		return subsumer
+	}
+	for _, member := range set {
+		if member.Equal(cl) {
+			return member
+		}
+	}
+	return nil
+}
+
+// subsumes reports whether cl is an experienced, reliable and unmarked
+// classifier with the same action and effect as other and a more
+// general condition.
+func (a *Acs2) subsumes(cl, other *Classifier) bool {
+	return cl.Action == other.Action && equalInts(cl.Effect, other.Effect) &&
+		cl.Exp > a.params.ThetaExp && cl.IsReliable(a.params.ThetaR) && !cl.IsMarked() &&
+		cl.IsMoreGeneralThan(other)
+}
+
+// ApplyReinforcementLearning moves the reward prediction of each member
+// of the action set towards reward plus Gamma times maxP, the best
+// prediction in the next state.
+func (a *Acs2) ApplyReinforcementLearning(actionSet []*Classifier, reward, maxP float64) {
+	for _, cl := range actionSet {
+		cl.Reward += a.params.Beta * (reward + a.params.Gamma*maxP - cl.Reward)
+		cl.ImmediateReward += a.params.Beta * (reward - cl.ImmediateReward)
+	}
+}
+
+// ApplyGeneticAlgorithm runs the genetic generalization on the action
+// set if DoGa is set and the set has on average gone ThetaGa steps
+// without it. Two parents chosen in proportion to the cube of their
+// quality produce children by generalizing mutation and crossover, and
+// the weakest members of the set make room for them.
+func (a *Acs2) ApplyGeneticAlgorithm(actionSet []*Classifier, ruleSet *Population, step int64) {
+	if !a.params.DoGa || len(actionSet) == 0 {
+		return
+	}
+	numerositySum := 0
+	timeStampSum := int64(0)
+	for _, cl := range actionSet {
+		numerositySum += cl.Numerosity
+		timeStampSum += cl.TimeStamp * int64(cl.Numerosity)
+	}
+	if float64(step)-float64(timeStampSum)/float64(numerositySum) <= float64(a.params.ThetaGa) {
+		return
+	}
+	for _, cl := range actionSet {
+		cl.TimeStamp = step
+	}
+	child1 := a.selectParent(actionSet).copy()
+	child2 := a.selectParent(actionSet).copy()
+	for _, child := range []*Classifier{child1, child2} {
+		child.Exp = 1
+		child.TimeStamp = step
+		a.generalizingMutation(child)
+	}
+	if a.rnd.Float64() < a.params.Chi && equalInts(child1.Effect, child2.Effect) {
+		a.crossover(child1, child2)
+	}
+	child1.Quality /= 2
+	child2.Quality /= 2
+	actionSet = a.deleteFromActionSet(actionSet, ruleSet, 2)
+	for _, child := range []*Classifier{child1, child2} {
+		if child.Specificity() == 0 {
+			continue
+		}
+		if old := a.findSubsumerOrEqual(child, actionSet); old != nil {
+			old.Numerosity++
+		} else {
+			ruleSet.Add(child)
+			actionSet = append(actionSet, child)
+		}
+	}
+}
+
+func (a *Acs2) selectParent(actionSet []*Classifier) *Classifier {
+	sum := 0.0
+	for _, cl := range actionSet {
+		sum += math.Pow(cl.Quality, 3)
+	}
+	choicePoint := a.rnd.Float64() * sum
+	sum = 0.0
+	for _, cl := range actionSet {
+		sum += math.Pow(cl.Quality, 3)
+		if sum > choicePoint {
+			return cl
+		}
+	}
+	return actionSet[a.rnd.Intn(len(actionSet))]
+}
+
+// generalizingMutation sets each specified attribute of the condition to
+// DontCare with probability Mu.
+func (a *Acs2) generalizingMutation(cl *Classifier) {
+	for i, v := range cl.Condition {
+		if v != DontCare && a.rnd.Float64() < a.params.Mu {
+			cl.Condition[i] = DontCare
+		}
+	}
+}
+
+// crossover swaps the conditions of the two classifiers between two
+// random points and gives both their mean quality.
+func (a *Acs2) crossover(cl1, cl2 *Classifier) {
+	x := a.rnd.Intn(len(cl1.Condition) + 1)
+	y := a.rnd.Intn(len(cl1.Condition) + 1)
+	if x > y {
+		x, y = y, x
+	}
+	for i := x; i < y; i++ {
+		cl1.Condition[i], cl2.Condition[i] = cl2.Condition[i], cl1.Condition[i]
+	}
+	q := (cl1.Quality + cl2.Quality) / 2
+	cl1.Quality, cl2.Quality = q, q
+}
+
+// deleteFromActionSet deletes micro-classifiers of the lowest quality
+// from the action set until room remains for insertSize more within
+// ThetaAs. Ties are broken in favour of deleting marked classifiers,
+// then those applied least often.
+func (a *Acs2) deleteFromActionSet(actionSet []*Classifier, ruleSet *Population, insertSize int) []*Classifier {
+	for {
+		size := 0
+		for _, cl := range actionSet {
+			size += cl.Numerosity
+		}
+		if size+insertSize <= a.params.ThetaAs || len(actionSet) == 0 {
+			return actionSet
+		}
+		victim := actionSet[0]
+		for _, cl := range actionSet[1:] {
+			if worse(cl, victim) {
+				victim = cl
+			}
+		}
+		if victim.Numerosity > 1 {
+			victim.Numerosity--
+		} else {
+			ruleSet.Remove(victim)
+			actionSet = remove(actionSet, victim)
+		}
+	}
+}
+
+func worse(cl, than *Classifier) bool {
+	if cl.Quality != than.Quality {
+		return cl.Quality < than.Quality
+	}
+	if cl.IsMarked() != than.IsMarked() {
+		return cl.IsMarked()
+	}
+	return cl.ApplicationAverage > than.ApplicationAverage
+}
+
+// Anticipate returns the state the population anticipates after action
+// in state, according to the reliable classifier of highest quality that
+// matches it, and false if there is none.
+func (a *Acs2) Anticipate(ruleSet *Population, state []int, action int) ([]int, bool) {
+	var best *Classifier
+	for _, cl := range ruleSet.Classifiers() {
+		if cl.Action == action && cl.IsReliable(a.params.ThetaR) && cl.Matches(state) &&
+			(best == nil || cl.Quality > best.Quality) {
+			best = cl
+		}
+	}
+	if best == nil {
+		return nil, false
+	}
+	return best.Anticipation(state), true
+}
+
+// Assess runs samples exploit episodes on the problem, always choosing
+// the best action and learning nothing, and measures the performance of
+// the population as xcs.Xcs.Assess does. Episodes are cut off after
+// Schedule.EvalStepCap steps if it is set. SystemError is not measured,
+// so a problem advertising it is judged by accuracy instead.
+func (a *Acs2) Assess(problem mli.Problem, ruleSet *Population, samples int) (xcs.Assessment, error) {
+	if samples < 1 {
+		return xcs.Assessment{}, xcs.InvalidParam("samples", samples, "must be at least 1")
+	}
+	stepCap := a.schedule.EvalStepCap
+	numCorrect := 0
+	numSteps := 0
+	rewardSum := 0.0
+	for j := 0; j < samples; j++ {
+		problem.Reset()
+		steps := 0
+		correct := false
+		for !problem.IsAtEndState() && (stepCap == 0 || steps < stepCap) {
+			state := problem.ObtainInput().GetInputs()
+			reward := problem.Effect(a.bestAction(a.matchSet(ruleSet, state)))
+			rewardSum += float64(reward)
+			steps++
+			if problem.IsAtEndState() {
+				correct = reward == 1000
+			}
+		}
+		if correct {
+			numCorrect++
+		} else if stepCap > 0 {
+			steps = stepCap
+		}
+		numSteps += steps
+	}
+	result := xcs.Assessment{
+		Metric:            mli.MetricAccuracy,
+		ProportionCorrect: float64(numCorrect) / float64(samples),
+		AverageSteps:      float64(numSteps) / float64(samples),
+		AverageReward:     rewardSum / float64(samples),
+	}
+	if a.schedule.Metric != "" {
+		result.Metric = a.schedule.Metric
+	} else if mp, ok := problem.(mli.MetricProblem); ok {
+		result.Metric = mp.Metric()
+	}
+	switch result.Metric {
+	case mli.MetricSteps:
+		result.Value = result.AverageSteps
+	case mli.MetricReward:
+		result.Value = result.AverageReward
+	default:
+		result.Metric = mli.MetricAccuracy
+		result.Value = result.ProportionCorrect
+	}
+	return result, nil
+}
+
+// Evaluate assesses the population with Schedule.EvalSamples episodes
+// and reports the result to the observer. AverageFitness reports the
+// mean quality of the micro-classifiers.
+func (a *Acs2) Evaluate(problem mli.Problem, ruleSet *Population, macroStep int) error {
+	result, err := a.Assess(problem, ruleSet, a.schedule.EvalSamples)
+	if err != nil {
+		return err
+	}
+	micro := ruleSet.MicroSize()
+	generality, quality := 0.0, 0.0
+	for _, cl := range ruleSet.Classifiers() {
+		generality += cl.GetGenerality() * float64(cl.Numerosity)
+		quality += cl.Quality * float64(cl.Numerosity)
+	}
+	if micro > 0 {
+		generality /= float64(micro)
+		quality /= float64(micro)
+	}
+	a.observer.EvaluationFinished(xcs.EvaluationEvent{
+		MacroStep:         macroStep,
+		Samples:           a.schedule.EvalSamples,
+		Metric:            result.Metric,
+		Value:             result.Value,
+		ProportionCorrect: result.ProportionCorrect,
+		AverageSteps:      result.AverageSteps,
+		AverageReward:     result.AverageReward,
+		MacroClassifiers:  ruleSet.Len(),
+		MicroClassifiers:  int32(micro),
+		AverageGenerality: generality,
+		AverageFitness:    quality,
+	})
+	return nil
+}
@@ -0,0 +1,109 @@
+package acs2_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/acs2"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/maze"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// finishCounter counts the runs reported finished.
+type finishCounter struct {
+	xcs.NopObserver
+	finished int
+}
+
+func (f *finishCounter) TrainingFinished(e xcs.TrainingEvent) {
+	f.finished++
+}
+
+func TestLearnsWoods1(t *testing.T) {
+	rnd := rng.New(1)
+	problem, err := maze.New("woods1", 50, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := acs2.New(acs2.DefaultParams(), rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	observer := &finishCounter{}
+	a.SetObserver(observer)
+	if err := a.SetSchedule(xcs.Schedule{Iterations: 3000, EvalStepCap: 50}); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := a.Train(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if observer.finished != 1 {
+		t.Errorf("TrainingFinished reported %v times, want once", observer.finished)
+	}
+
+	assessment, err := a.Assess(problem, ruleSet, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The optimum is 1.68 steps and a random walk takes about 27.
+	if assessment.AverageSteps > 3 {
+		t.Errorf("average steps to food = %v, want at most 3", assessment.AverageSteps)
+	}
+
+	// Walk at random and compare each anticipated state with the next.
+	correct, total := 0, 0
+	for episode := 0; episode < 200; episode++ {
+		problem.Reset()
+		state := problem.ObtainInput().GetInputs()
+		for !problem.IsAtEndState() {
+			action := rnd.Intn(8)
+			anticipated, ok := a.Anticipate(ruleSet, state, action)
+			problem.Effect(action)
+			if problem.IsAtEndState() {
+				break
+			}
+			next := problem.Observe().GetInputs()
+			total++
+			if ok && reflect.DeepEqual(anticipated, next) {
+				correct++
+			}
+			state = next
+		}
+	}
+	if float64(correct) < 0.9*float64(total) {
+		t.Errorf("anticipated %v of %v transitions, want at least 90%%", correct, total)
+	}
+}
+
+func TestTrainNeedsObservableProblem(t *testing.T) {
+	a, err := acs2.New(acs2.DefaultParams(), rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var problem mli.Problem = blindProblem{}
+	if _, err := a.Train(problem); !errors.Is(err, acs2.ErrNotObservable) {
+		t.Errorf("Train on a problem without Observe: err = %v, want ErrNotObservable", err)
+	}
+}
+
+// blindProblem is a Problem without Observe; Train must reject it before
+// calling any of its methods.
+type blindProblem struct {
+	mli.Problem
+}
+
+func TestRejectsSystemErrorMetric(t *testing.T) {
+	a, err := acs2.New(acs2.DefaultParams(), rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule := xcs.DefaultSchedule()
+	schedule.Metric = mli.MetricSystemError
+	if err := a.SetSchedule(schedule); !errors.Is(err, xcs.ErrInvalidParam) {
+		t.Errorf("SetSchedule with the systemError metric returned %v", err)
+	}
+}
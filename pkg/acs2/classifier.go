@@ -0,0 +1,292 @@
+package acs2
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DontCare is the symbol '#'. In a condition it matches any value; in an
+// effect it anticipates that the attribute keeps its value.
+const DontCare = -1
+
+// Classifier is a condition-action-effect rule: in a state matching
+// Condition, Action is anticipated to lead to a state in which the
+// attributes specified in Effect take those values and the others do
+// not change. Quality measures how often the anticipation held and
+// Reward predicts the payoff of the action. Mark records the values of
+// unspecified attributes in states where the anticipation failed.
+type Classifier struct {
+	Condition          []int   `json:"condition"`
+	Action             int     `json:"action"`
+	Effect             []int   `json:"effect"`
+	Mark               [][]int `json:"mark"`
+	Quality            float64 `json:"quality"`
+	Reward             float64 `json:"reward"`
+	ImmediateReward    float64 `json:"immediateReward"`
+	Numerosity         int     `json:"numerosity"`
+	Exp                int     `json:"exp"`
+	TimeStamp          int64   `json:"timeStamp"`
+	AlpTimeStamp       int64   `json:"alpTimeStamp"`
+	ApplicationAverage float64 `json:"applicationAverage"`
+}
+
+func newClassifier(length int, action int, step int64) *Classifier {
+	cl := &Classifier{
+		Condition:    make([]int, length),
+		Action:       action,
+		Effect:       make([]int, length),
+		Mark:         make([][]int, length),
+		Quality:      0.5,
+		Numerosity:   1,
+		Exp:          1,
+		TimeStamp:    step,
+		AlpTimeStamp: step,
+	}
+	for i := range cl.Condition {
+		cl.Condition[i] = DontCare
+		cl.Effect[i] = DontCare
+	}
+	return cl
+}
+
+func (c *Classifier) GetCondition() []int {
+	return c.Condition
+}
+
+func (c *Classifier) GetAction() int {
+	return c.Action
+}
+
+func (c *Classifier) GetEffect() []int {
+	return c.Effect
+}
+
+func (c *Classifier) GetQuality() float64 {
+	return c.Quality
+}
+
+func (c *Classifier) GetReward() float64 {
+	return c.Reward
+}
+
+func (c *Classifier) GetNumerosity() int {
+	return c.Numerosity
+}
+
+func (c *Classifier) GetExperience() int {
+	return c.Exp
+}
+
+// IsReliable reports whether the quality of the classifier exceeds
+// thetaR.
+func (c *Classifier) IsReliable(thetaR float64) bool {
+	return c.Quality > thetaR
+}
+
+func (c *Classifier) Matches(state []int) bool {
+	for i, v := range c.Condition {
+		if v != DontCare && v != state[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// AnticipatesChange reports whether the effect specifies any attribute.
+func (c *Classifier) AnticipatesChange() bool {
+	for _, v := range c.Effect {
+		if v != DontCare {
+			return true
+		}
+	}
+	return false
+}
+
+// Anticipates reports whether the classifier anticipated the change from
+// previous to next: every attribute specified in the effect changed to
+// that value, and no other attribute changed.
+func (c *Classifier) Anticipates(previous, next []int) bool {
+	for i, v := range c.Effect {
+		if v == DontCare {
+			if previous[i] != next[i] {
+				return false
+			}
+		} else if v != next[i] || previous[i] == next[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Anticipation returns the state the classifier anticipates after acting
+// in state.
+func (c *Classifier) Anticipation(state []int) []int {
+	next := append([]int(nil), state...)
+	for i, v := range c.Effect {
+		if v != DontCare {
+			next[i] = v
+		}
+	}
+	return next
+}
+
+func (c *Classifier) IsMarked() bool {
+	for _, values := range c.Mark {
+		if len(values) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMark adds the values of state at the unspecified attributes of the
+// condition to the mark.
+func (c *Classifier) SetMark(state []int) {
+	for i, v := range c.Condition {
+		if v != DontCare || containsInt(c.Mark[i], state[i]) {
+			continue
+		}
+		c.Mark[i] = append(c.Mark[i], state[i])
+		sort.Ints(c.Mark[i])
+	}
+}
+
+func containsInt(values []int, v int) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Specificity returns the number of specified attributes of the
+// condition.
+func (c *Classifier) Specificity() int {
+	n := 0
+	for _, v := range c.Condition {
+		if v != DontCare {
+			n++
+		}
+	}
+	return n
+}
+
+// GetGenerality returns the proportion of unspecified attributes of the
+// condition.
+func (c *Classifier) GetGenerality() float64 {
+	if len(c.Condition) == 0 {
+		return 1
+	}
+	return 1 - float64(c.Specificity())/float64(len(c.Condition))
+}
+
+// Equal reports whether the two classifiers have the same condition,
+// action and effect.
+func (c *Classifier) Equal(other *Classifier) bool {
+	return c.Action == other.Action && equalInts(c.Condition, other.Condition) && equalInts(c.Effect, other.Effect)
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsMoreGeneralThan reports whether the condition matches every state the
+// other condition matches and has fewer specified attributes.
+func (c *Classifier) IsMoreGeneralThan(other *Classifier) bool {
+	if c.Specificity() >= other.Specificity() {
+		return false
+	}
+	for i, v := range c.Condition {
+		if v != DontCare && v != other.Condition[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// copy returns an unmarked copy of the classifier with a numerosity of
+// 1.
+func (c *Classifier) copy() *Classifier {
+	cl := *c
+	cl.Condition = append([]int(nil), c.Condition...)
+	cl.Effect = append([]int(nil), c.Effect...)
+	cl.Mark = make([][]int, len(c.Mark))
+	cl.Numerosity = 1
+	return &cl
+}
+
+func (c *Classifier) ToString() string {
+	return symbols(c.Condition) + "-" + strconv.Itoa(c.Action) + "-" + symbols(c.Effect) +
+		" [Q: " + strconv.FormatFloat(c.Quality, 'f', 3, 64) +
+		"; R: " + strconv.FormatFloat(c.Reward, 'f', 3, 64) +
+		"; NUM: " + strconv.Itoa(c.Numerosity) +
+		"; EXP: " + strconv.Itoa(c.Exp) + "]"
+}
+
+func symbols(values []int) string {
+	builder := strings.Builder{}
+	for _, v := range values {
+		if v == DontCare {
+			builder.WriteString("#")
+		} else {
+			builder.WriteString(strconv.Itoa(v))
+		}
+	}
+	return builder.String()
+}
+
+// Population is the set of macro-classifiers of an ACS2 run.
+type Population struct {
+	classifiers []*Classifier
+}
+
+func NewPopulation() *Population {
+	return &Population{}
+}
+
+func (p *Population) Len() int {
+	return len(p.classifiers)
+}
+
+// Classifiers returns the members of the population. The slice belongs
+// to the population and is only valid until it next changes.
+func (p *Population) Classifiers() []*Classifier {
+	return p.classifiers
+}
+
+// MicroSize returns the sum of the numerosities of the members.
+func (p *Population) MicroSize() int {
+	n := 0
+	for _, cl := range p.classifiers {
+		n += cl.Numerosity
+	}
+	return n
+}
+
+func (p *Population) Add(cl *Classifier) {
+	p.classifiers = append(p.classifiers, cl)
+}
+
+// Remove removes cl, reporting whether it was a member.
+func (p *Population) Remove(cl *Classifier) bool {
+	for i, member := range p.classifiers {
+		if member == cl {
+			last := len(p.classifiers) - 1
+			p.classifiers[i] = p.classifiers[last]
+			p.classifiers[last] = nil
+			p.classifiers = p.classifiers[:last]
+			return true
+		}
+	}
+	return false
+}
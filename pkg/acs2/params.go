@@ -0,0 +1,84 @@
+package acs2
+
+import "github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+
+// Params holds the learning parameters of an ACS2 run. The names follow
+// Butz and Stolzmann (2002); DefaultParams returns the values used there.
+type Params struct {
+	// Beta is the learning rate of quality, reward and the application
+	// average, and Gamma the discount of reward.
+	Beta  float64 `json:"beta" yaml:"beta" toml:"beta"`
+	Gamma float64 `json:"gamma" yaml:"gamma" toml:"gamma"`
+	// ThetaI is the quality below which a classifier is removed, and
+	// ThetaR the quality above which it is reliable.
+	ThetaI float64 `json:"thetaI" yaml:"thetaI" toml:"thetaI"`
+	ThetaR float64 `json:"thetaR" yaml:"thetaR" toml:"thetaR"`
+	// Epsilon is the probability of a random action when exploring.
+	Epsilon float64 `json:"epsilon" yaml:"epsilon" toml:"epsilon"`
+	// UMax limits the number of specified attributes of a condition
+	// specialized by the anticipatory learning process; 0 means no limit.
+	UMax int `json:"uMax" yaml:"uMax" toml:"uMax"`
+	// DoGa enables genetic generalization: every ThetaGa steps on
+	// average, two classifiers of an action set breed with generalizing
+	// mutation at rate Mu and crossover at rate Chi, and classifiers are
+	// deleted while the action set holds more than ThetaAs
+	// micro-classifiers.
+	DoGa    bool    `json:"doGa" yaml:"doGa" toml:"doGa"`
+	ThetaGa int     `json:"thetaGa" yaml:"thetaGa" toml:"thetaGa"`
+	Mu      float64 `json:"mu" yaml:"mu" toml:"mu"`
+	Chi     float64 `json:"chi" yaml:"chi" toml:"chi"`
+	ThetaAs int     `json:"thetaAs" yaml:"thetaAs" toml:"thetaAs"`
+	// ThetaExp is the experience a classifier needs to subsume others.
+	ThetaExp  int `json:"thetaExp" yaml:"thetaExp" toml:"thetaExp"`
+	MaxAction int `json:"maxAction" yaml:"maxAction" toml:"maxAction"`
+}
+
+func DefaultParams() Params {
+	return Params{
+		Beta:      0.05,
+		Gamma:     0.95,
+		ThetaI:    0.1,
+		ThetaR:    0.9,
+		Epsilon:   0.5,
+		UMax:      0,
+		DoGa:      true,
+		ThetaGa:   100,
+		Mu:        0.3,
+		Chi:       0.8,
+		ThetaAs:   20,
+		ThetaExp:  20,
+		MaxAction: 7,
+	}
+}
+
+// Validate reports the first parameter that lies outside its permitted
+// range.
+func (p Params) Validate() error {
+	switch {
+	case p.Beta <= 0 || p.Beta > 1:
+		return xcs.InvalidParam("beta", p.Beta, "must be in (0, 1]")
+	case p.Gamma < 0 || p.Gamma >= 1:
+		return xcs.InvalidParam("gamma", p.Gamma, "must be in [0, 1)")
+	case p.ThetaI < 0 || p.ThetaI >= 1:
+		return xcs.InvalidParam("thetaI", p.ThetaI, "must be in [0, 1)")
+	case p.ThetaR <= p.ThetaI || p.ThetaR > 1:
+		return xcs.InvalidParam("thetaR", p.ThetaR, "must be above thetaI and at most 1")
+	case p.Epsilon < 0 || p.Epsilon > 1:
+		return xcs.InvalidParam("epsilon", p.Epsilon, "must be in [0, 1]")
+	case p.UMax < 0:
+		return xcs.InvalidParam("uMax", p.UMax, "must not be negative")
+	case p.ThetaGa < 0:
+		return xcs.InvalidParam("thetaGa", p.ThetaGa, "must not be negative")
+	case p.Mu < 0 || p.Mu > 1:
+		return xcs.InvalidParam("mu", p.Mu, "must be in [0, 1]")
+	case p.Chi < 0 || p.Chi > 1:
+		return xcs.InvalidParam("chi", p.Chi, "must be in [0, 1]")
+	case p.ThetaAs < 2:
+		return xcs.InvalidParam("thetaAs", p.ThetaAs, "must be at least 2")
+	case p.ThetaExp < 0:
+		return xcs.InvalidParam("thetaExp", p.ThetaExp, "must not be negative")
+	case p.MaxAction < 0:
+		return xcs.InvalidParam("maxAction", p.MaxAction, "must not be negative")
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package acs2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// FormatVersion is the version written by Save.
+const FormatVersion = 1
+
+// algorithmName marks saved ACS2 populations, which package xcs cannot
+// read.
+const algorithmName = "acs2"
+
+type savedPopulation struct {
+	Version     int           `json:"version"`
+	Algorithm   string        `json:"algorithm"`
+	Params      Params        `json:"params"`
+	Classifiers []*Classifier `json:"classifiers"`
+}
+
+// Save writes the population and the parameters used to evolve it as
+// JSON.
+func Save(w io.Writer, params Params, ruleSet *Population) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(savedPopulation{FormatVersion, algorithmName, params, ruleSet.Classifiers()})
+}
+
+// Load reads a population written by Save.
+func Load(r io.Reader) (Params, *Population, error) {
+	saved := savedPopulation{Params: DefaultParams()}
+	if err := json.NewDecoder(r).Decode(&saved); err != nil {
+		return Params{}, nil, err
+	}
+	if saved.Algorithm != algorithmName {
+		return Params{}, nil, errors.New("not an ACS2 population")
+	}
+	if saved.Version < 1 || saved.Version > FormatVersion {
+		return Params{}, nil, fmt.Errorf("unsupported population format version %v", saved.Version)
+	}
+	if err := saved.Params.Validate(); err != nil {
+		return Params{}, nil, err
+	}
+	ruleSet := NewPopulation()
+	for i, cl := range saved.Classifiers {
+		if cl == nil || len(cl.Effect) != len(cl.Condition) || len(cl.Mark) != len(cl.Condition) {
+			return Params{}, nil, fmt.Errorf("classifier %v: condition, effect and mark differ in length", i)
+		}
+		ruleSet.Add(cl)
+	}
+	return saved.Params, ruleSet, nil
+}
+
+func SaveFile(path string, params Params, ruleSet *Population) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := Save(f, params, ruleSet); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadFile(path string) (Params, *Population, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Params{}, nil, err
+	}
+	defer f.Close()
+	params, ruleSet, err := Load(f)
+	if err != nil {
+		return Params{}, nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return params, ruleSet, nil
+}
@@ -0,0 +1,354 @@
+// Package config loads the description of an experiment -- the problem,
+// the XCS parameters and the run schedule -- from a JSON, YAML or TOML
+// file.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/acs2"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/function"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/maze"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/ucs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/zcs"
+)
+
+type Config struct {
+	// Algorithm is "xcs", which learns from rewards, "ucs", which
+	// learns from the answers of a single-step problem, "zcs", which
+	// learns from rewards by strength rather than accuracy, or "acs2",
+	// which learns what its actions do as well as their rewards.
+	Algorithm string `json:"algorithm" yaml:"algorithm" toml:"algorithm"`
+	// Seed seeds the random number generator shared by the problem and
+	// the algorithm. Zero means a seed taken from the clock.
+	Seed    int64        `json:"seed" yaml:"seed" toml:"seed"`
+	Problem Problem      `json:"problem" yaml:"problem" toml:"problem"`
+	Run     xcs.Schedule `json:"run" yaml:"run" toml:"run"`
+	// Xcs holds the learning parameters of XCS, UCS and ZCS. Settings
+	// left out take the defaults of the algorithm: ucs.DefaultParams for
+	// UCS and xcs.DefaultParams otherwise.
+	Xcs xcs.Params `json:"xcs" yaml:"xcs" toml:"xcs"`
+	// Zcs holds the settings of ZCS that are not in Xcs; ZCS takes the
+	// others from Xcs.
+	Zcs zcs.Params `json:"zcs" yaml:"zcs" toml:"zcs"`
+	// Acs2 holds all the settings of ACS2, which uses none from Xcs.
+	Acs2 acs2.Params `json:"acs2" yaml:"acs2" toml:"acs2"`
+}
+
+// Problem names the problem type and its settings. Size is the number of
+// inputs; Function names the target of the "function" type; Maze names a
+// built-in maze or a map file for the "maze" type, whose episodes end
+// after StepLimit steps.
+type Problem struct {
+	Type      string `json:"type" yaml:"type" toml:"type"`
+	Size      int    `json:"size" yaml:"size" toml:"size"`
+	Function  string `json:"function" yaml:"function" toml:"function"`
+	Maze      string `json:"maze" yaml:"maze" toml:"maze"`
+	StepLimit int    `json:"stepLimit" yaml:"stepLimit" toml:"stepLimit"`
+}
+
+// Default returns the configuration used when no file is given: the
+// 6-bit multiplexer with the default parameters and schedule.
+func Default() Config {
+	return Config{
+		Algorithm: "xcs",
+		Problem:   Problem{Type: "multiplexer", Size: 6, StepLimit: maze.DefaultStepLimit},
+		Run:       xcs.DefaultSchedule(),
+		Xcs:       xcs.DefaultParams(),
+		Zcs:       zcs.DefaultParams(),
+		Acs2:      acs2.DefaultParams(),
+	}
+}
+
+// DefaultFor returns Default with the learning parameters of the
+// algorithm.
+func DefaultFor(algorithm string) Config {
+	cfg := Default()
+	cfg.Algorithm = algorithm
+	cfg.Xcs = defaultParams(algorithm)
+	return cfg
+}
+
+func defaultParams(algorithm string) xcs.Params {
+	if algorithm == "ucs" {
+		return ucs.DefaultParams()
+	}
+	return xcs.DefaultParams()
+}
+
+// Load reads the file at path, choosing the format from its extension
+// (.json, .yaml, .yml or .toml). Settings absent from the file keep
+// their default values; unknown keys are reported as errors.
+func Load(path string) (Config, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	cfg, err := Parse(data, format)
+	if err != nil {
+		return Config{}, fmt.Errorf("%v: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse decodes data in the given format ("json", "yaml", "yml" or
+// "toml") over the defaults of the algorithm it names and validates the
+// result.
+func Parse(data []byte, format string) (Config, error) {
+	cfg := Default()
+	if err := decode(data, format, &cfg); err != nil {
+		return Config{}, err
+	}
+	if cfg.Algorithm != "xcs" {
+		cfg = DefaultFor(cfg.Algorithm)
+		if err := decode(data, format, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// decode decodes data over cfg, reporting unknown keys as errors.
+func decode(data []byte, format string, cfg *Config) error {
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(cfg)
+	case "yaml", "yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	case "toml":
+		md, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return err
+		}
+		if undecoded := md.Undecoded(); len(undecoded) > 0 {
+			keys := make([]string, len(undecoded))
+			for i, k := range undecoded {
+				keys[i] = k.String()
+			}
+			sort.Strings(keys)
+			return fmt.Errorf("unknown keys: %v", strings.Join(keys, ", "))
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported configuration format %q", format)
+}
+
+func (c Config) Validate() error {
+	switch c.Algorithm {
+	case "xcs", "ucs", "zcs", "acs2":
+	default:
+		return fmt.Errorf("unknown algorithm %q", c.Algorithm)
+	}
+	if c.Algorithm == "ucs" && c.Problem.Type == "maze" {
+		return fmt.Errorf("algorithm %q needs a single-step problem, not %q", c.Algorithm, c.Problem.Type)
+	}
+	if err := c.Xcs.Validate(); err != nil {
+		return err
+	}
+	if err := c.Zcs.Validate(); err != nil {
+		return err
+	}
+	if err := c.Acs2.Validate(); err != nil {
+		return err
+	}
+	if err := c.Run.Validate(); err != nil {
+		return err
+	}
+	switch c.Problem.Type {
+	case "multiplexer", "real-multiplexer", "function", "maze":
+		problem, err := c.NewProblem(nil)
+		if err != nil {
+			return err
+		}
+		if _, ok := problem.(mli.ObservableProblem); c.Algorithm == "acs2" && !ok {
+			return fmt.Errorf("algorithm %q needs a problem that exposes its state, not %q", c.Algorithm, c.Problem.Type)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown problem type %q", c.Problem.Type)
+}
+
+// NewRand returns a generator seeded with Seed. A zero Seed is first
+// replaced by one taken from the clock, so that the seed actually used
+// can be read back and reported.
+func (c *Config) NewRand() *rng.Rand {
+	if c.Seed == 0 {
+		c.Seed = time.Now().UnixNano()
+	}
+	return rng.New(c.Seed)
+}
+
+// NewProblem builds the problem described by the configuration.
+func (c Config) NewProblem(rnd rng.Generator) (mli.Problem, error) {
+	switch c.Problem.Type {
+	case "real-multiplexer":
+		return multiplexer.NewReal(c.Problem.Size, rnd)
+	case "function":
+		return function.New(c.Problem.Function, c.Problem.Size, rnd)
+	case "maze":
+		return maze.New(c.Problem.Maze, c.Problem.StepLimit, rnd)
+	}
+	return multiplexer.New(c.Problem.Size, rnd)
+}
+
+// NewXcs builds an Xcs with the configured parameters and schedule.
+func (c Config) NewXcs(rnd *rng.Rand) (*xcs.Xcs, error) {
+	alg, err := xcs.New(c.Xcs, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.SetSchedule(c.Run); err != nil {
+		return nil, err
+	}
+	return alg, nil
+}
+
+// NewUcs builds a Ucs with the configured parameters and schedule.
+func (c Config) NewUcs(rnd *rng.Rand) (*ucs.Ucs, error) {
+	alg, err := ucs.New(c.Xcs, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.SetSchedule(c.Run); err != nil {
+		return nil, err
+	}
+	return alg, nil
+}
+
+// NewZcs builds a Zcs with the configured parameters and schedule.
+func (c Config) NewZcs(rnd *rng.Rand) (*zcs.Zcs, error) {
+	alg, err := zcs.New(c.Xcs, c.Zcs, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.SetSchedule(c.Run); err != nil {
+		return nil, err
+	}
+	return alg, nil
+}
+
+// NewAcs2 builds an Acs2 with the configured parameters and schedule.
+func (c Config) NewAcs2(rnd *rng.Rand) (*acs2.Acs2, error) {
+	alg, err := acs2.New(c.Acs2, rnd)
+	if err != nil {
+		return nil, err
+	}
+	if err := alg.SetSchedule(c.Run); err != nil {
+		return nil, err
+	}
+	return alg, nil
+}
+
+// Set overrides a single setting named by its dotted key, for example
+// "xcs.maxPop" or "run.iterations". The value is read as JSON where
+// possible and as a plain string otherwise. Changing the algorithm moves
+// the learning parameters still at the defaults of the old algorithm to
+// those of the new one. The result is not validated; call Validate once
+// all overrides have been applied.
+func (c *Config) Set(key, value string) error {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return err
+	}
+	parts := strings.Split(key, ".")
+	node := tree
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := node[part].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		node = child
+	}
+	last := parts[len(parts)-1]
+	if _, ok := node[last]; !ok {
+		return fmt.Errorf("unknown key %q", key)
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		v = value
+	}
+	node[last] = v
+	if algorithm, ok := tree["algorithm"].(string); ok && algorithm != c.Algorithm {
+		params, ok := tree["xcs"].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unknown key %q", "xcs")
+		}
+		if err := rebaseDefaults(params, defaultParams(c.Algorithm), defaultParams(algorithm)); err != nil {
+			return err
+		}
+	}
+	if data, err = json.Marshal(tree); err != nil {
+		return err
+	}
+	var updated Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&updated); err != nil {
+		return fmt.Errorf("%v: %w", key, err)
+	}
+	*c = updated
+	return nil
+}
+
+// rebaseDefaults replaces the settings in params, a decoded JSON object,
+// that equal those of from with those of to.
+func rebaseDefaults(params map[string]interface{}, from, to xcs.Params) error {
+	fromTree, err := paramsTree(from)
+	if err != nil {
+		return err
+	}
+	toTree, err := paramsTree(to)
+	if err != nil {
+		return err
+	}
+	for k, v := range params {
+		if reflect.DeepEqual(v, fromTree[k]) {
+			params[k] = toTree[k]
+		}
+	}
+	return nil
+}
+
+func paramsTree(p xcs.Params) (map[string]interface{}, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, err
+	}
+	return tree, nil
+}
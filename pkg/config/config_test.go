@@ -0,0 +1,74 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/config"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/ucs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+func TestParseFormats(t *testing.T) {
+	files := map[string]string{
+		"json": `{"problem": {"size": 11}, "xcs": {"maxPop": 800}}`,
+		"yaml": "problem:\n  size: 11\nxcs:\n  maxPop: 800\n",
+		"toml": "[problem]\nsize = 11\n[xcs]\nmaxPop = 800\n",
+	}
+	for format, data := range files {
+		cfg, err := config.Parse([]byte(data), format)
+		if err != nil {
+			t.Fatalf("%v: %v", format, err)
+		}
+		want := config.Default()
+		want.Problem.Size = 11
+		want.Xcs.MaxPop = 800
+		if cfg != want {
+			t.Errorf("%v: got %+v, want %+v", format, cfg, want)
+		}
+	}
+}
+
+func TestParseRejectsUnknownKeys(t *testing.T) {
+	files := map[string]string{
+		"json": `{"xcs": {"maxPopulation": 800}}`,
+		"yaml": "xcs:\n  maxPopulation: 800\n",
+		"toml": "[xcs]\nmaxPopulation = 800\n",
+	}
+	for format, data := range files {
+		if _, err := config.Parse([]byte(data), format); err == nil {
+			t.Errorf("%v: unknown key accepted", format)
+		}
+	}
+}
+
+func TestUcsDefaults(t *testing.T) {
+	cfg, err := config.Parse([]byte("algorithm: ucs\nxcs:\n  maxPop: 400\n"), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ucs.DefaultParams()
+	want.MaxPop = 400
+	if cfg.Xcs != want {
+		t.Errorf("parsed UCS parameters %+v, want %+v", cfg.Xcs, want)
+	}
+
+	cfg = config.Default()
+	for _, s := range [][2]string{{"xcs.nu", "7"}, {"algorithm", "ucs"}} {
+		if err := cfg.Set(s[0], s[1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Xcs.ErrorZero != ucs.DefaultParams().ErrorZero || cfg.Xcs.Nu != 7 {
+		t.Errorf("after setting the algorithm to ucs, errorZero = %v and nu = %v, want %v and 7", cfg.Xcs.ErrorZero, cfg.Xcs.Nu, ucs.DefaultParams().ErrorZero)
+	}
+
+	if err := cfg.Set("algorithm", "xcs"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Xcs.ErrorZero != xcs.DefaultParams().ErrorZero {
+		t.Errorf("after setting the algorithm back to xcs, errorZero = %v, want %v", cfg.Xcs.ErrorZero, xcs.DefaultParams().ErrorZero)
+	}
+}
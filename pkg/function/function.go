@@ -0,0 +1,134 @@
+// Package function provides function approximation problems, as used to
+// evaluate XCS with computed prediction (XCSF). Each episode presents a
+// point drawn uniformly from [0, 1]^n and the reward is the value of the
+// target function at that point, whatever the action.
+package function
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+)
+
+var ErrUnknownFunction = errors.New("unknown function")
+
+// targets holds the available functions of s, the sum of the inputs.
+var targets = map[string]func(s float64) float64{
+	"linear": func(s float64) float64 { return s },
+	"polynomial": func(s float64) float64 {
+		return 1 + s + s*s + s*s*s
+	},
+	"sine": func(s float64) float64 { return math.Sin(2 * math.Pi * s) },
+	"sine3": func(s float64) float64 {
+		return math.Sin(2*math.Pi*s) + math.Sin(4*math.Pi*s) + math.Sin(6*math.Pi*s)
+	},
+}
+
+// Names returns the names of the available target functions.
+func Names() []string {
+	names := make([]string, 0, len(targets))
+	for name := range targets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type Function struct {
+	Name       string
+	Dimensions int
+	Target     float64
+	EndState   bool
+	f          func(s float64) float64
+	rnd        rng.Generator
+}
+
+// New returns the named function of the given number of inputs, drawing
+// its points from rnd.
+func New(name string, dimensions int, rnd rng.Generator) (*Function, error) {
+	f, ok := targets[name]
+	if !ok {
+		return nil, fmt.Errorf("%w %q (available: %v)", ErrUnknownFunction, name, strings.Join(Names(), ", "))
+	}
+	if dimensions < 1 {
+		return nil, fmt.Errorf("function %q needs at least one input, not %v", name, dimensions)
+	}
+	return &Function{name, dimensions, 0, false, f, rnd}, nil
+}
+
+func (fn *Function) IsAtEndState() bool {
+	return fn.EndState
+}
+
+func (fn *Function) Reset() {
+	fn.EndState = false
+}
+
+func (fn *Function) ObtainInput() mli.DataItem {
+	values := make([]float64, fn.Dimensions)
+	sum := 0.0
+	for i := range values {
+		values[i] = fn.rnd.Float64()
+		sum += values[i]
+	}
+	fn.Target = fn.f(sum)
+	return &Point{values, fn.Target}
+}
+
+// Metric returns mli.MetricSystemError, the error of the approximation.
+func (fn *Function) Metric() mli.Metric {
+	return mli.MetricSystemError
+}
+
+// Effect returns the target rounded to the nearest integer; use
+// RealEffect for the exact value.
+func (fn *Function) Effect(action int) int {
+	return int(math.Round(fn.RealEffect(action)))
+}
+
+func (fn *Function) RealEffect(action int) float64 {
+	fn.EndState = true
+	return fn.Target
+}
+
+// Point is an input of a function together with its target value.
+type Point struct {
+	Values []float64
+	Target float64
+}
+
+func (p *Point) GetInputs() []int {
+	inputs := make([]int, len(p.Values))
+	for i, v := range p.Values {
+		inputs[i] = int(v)
+	}
+	return inputs
+}
+
+func (p *Point) GetRealInputs() []float64 {
+	return p.Values
+}
+
+// GetAnswer returns 0, the only action of a function approximation
+// problem.
+func (p *Point) GetAnswer() int {
+	return 0
+}
+
+func (p *Point) GetAttribute(n int) int {
+	return int(p.Values[n])
+}
+
+func (p *Point) ToString() string {
+	parts := make([]string, len(p.Values))
+	for i, v := range p.Values {
+		parts[i] = strconv.FormatFloat(v, 'f', 3, 64)
+	}
+	return strings.Join(parts, " ") + " --> " + strconv.FormatFloat(p.Target, 'f', 3, 64)
+}
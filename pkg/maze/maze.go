@@ -0,0 +1,289 @@
+// Package maze provides grid worlds in which an animat must find food,
+// the classic multi-step problems for learning classifier systems:
+// Woods1 (Wilson, 1994), Woods2 (Wilson, 1995) and Maze4, Maze5 and
+// Maze6 (Lanzi, 1997). Maps are text files of one character per cell;
+// the animat senses its eight neighbours, moves in one of eight
+// directions and is rewarded when it reaches food.
+package maze
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+)
+
+var ErrUnknownMaze = errors.New("unknown maze")
+
+// ErrInvalidMap is returned for maps that are not rectangular, contain
+// unknown symbols, or have an empty cell from which food is out of
+// reach.
+var ErrInvalidMap = errors.New("invalid maze map")
+
+//go:embed maps/*.txt
+var builtIn embed.FS
+
+// The symbols of a map. Q and G are the second kinds of obstacle and
+// food of Woods2; maps using them are sensed with three bits per cell
+// rather than two.
+const (
+	Empty     = '.'
+	Obstacle  = 'O'
+	ObstacleQ = 'Q'
+	Food      = 'F'
+	FoodG     = 'G'
+)
+
+// FoodReward is the reward for reaching food; every other step is
+// rewarded with 0.
+const FoodReward = 1000
+
+// DefaultStepLimit is the usual number of steps after which an episode
+// that has not found food is abandoned.
+const DefaultStepLimit = 50
+
+var codes = map[int]map[byte]string{
+	2: {Empty: "00", Obstacle: "10", Food: "11"},
+	3: {Empty: "000", Obstacle: "010", ObstacleQ: "011", Food: "110", FoodG: "111"},
+}
+
+// directions holds the moves of actions 0 to 7: north, then clockwise
+// to north-west. Sensors are read in the same order.
+var directions = [8][2]int{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+
+type Maze struct {
+	Name      string
+	Width     int
+	Height    int
+	StepLimit int
+	X         int
+	Y         int
+	Steps     int
+	EndState  bool
+	cells     [][]byte
+	bits      int
+	free      [][2]int
+	rnd       rng.Generator
+}
+
+// Names returns the names of the built-in mazes.
+func Names() []string {
+	entries, _ := builtIn.ReadDir("maps")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".txt"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New returns the named built-in maze, or the maze in the map file at
+// that path if there is no such built-in. Episodes start on an empty
+// cell drawn from rnd and end at food or after stepLimit steps.
+func New(name string, stepLimit int, rnd rng.Generator) (*Maze, error) {
+	data, err := builtIn.ReadFile("maps/" + strings.ToLower(name) + ".txt")
+	if err != nil {
+		if data, err = ioutil.ReadFile(name); err != nil {
+			return nil, fmt.Errorf("%w %q (available: %v, or a map file)", ErrUnknownMaze, name, strings.Join(Names(), ", "))
+		}
+		name = strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	}
+	return Parse(name, string(data), stepLimit, rnd)
+}
+
+// Parse reads a maze from its map, one line of symbols per row. The map
+// wraps around at its edges, as Woods1 and Woods2 do; the other mazes
+// are surrounded by obstacles.
+func Parse(name string, text string, stepLimit int, rnd rng.Generator) (*Maze, error) {
+	if stepLimit < 1 {
+		return nil, fmt.Errorf("maze %q: step limit must be at least 1, not %v", name, stepLimit)
+	}
+	m := &Maze{Name: name, StepLimit: stepLimit, bits: 2, rnd: rnd}
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		row := []byte(strings.TrimSpace(line))
+		if m.Width == 0 {
+			m.Width = len(row)
+		}
+		if len(row) != m.Width {
+			return nil, fmt.Errorf("%w %q: row %v has %v cells, expected %v", ErrInvalidMap, name, len(m.cells), len(row), m.Width)
+		}
+		for x, c := range row {
+			switch c {
+			case ObstacleQ, FoodG:
+				m.bits = 3
+			case Empty:
+				m.free = append(m.free, [2]int{x, len(m.cells)})
+			case Obstacle, Food:
+			default:
+				return nil, fmt.Errorf("%w %q: unknown symbol %q in row %v", ErrInvalidMap, name, c, len(m.cells))
+			}
+		}
+		m.cells = append(m.cells, row)
+	}
+	m.Height = len(m.cells)
+	if len(m.free) == 0 {
+		return nil, fmt.Errorf("%w %q: no empty cells", ErrInvalidMap, name)
+	}
+	for _, d := range m.distances() {
+		if d < 0 {
+			return nil, fmt.Errorf("%w %q: food cannot be reached from every empty cell", ErrInvalidMap, name)
+		}
+	}
+	return m, nil
+}
+
+func (m *Maze) cell(x, y int) byte {
+	return m.cells[(y%m.Height+m.Height)%m.Height][(x%m.Width+m.Width)%m.Width]
+}
+
+func isObstacle(c byte) bool {
+	return c == Obstacle || c == ObstacleQ
+}
+
+func isFood(c byte) bool {
+	return c == Food || c == FoodG
+}
+
+// distances returns the fewest steps to food from each empty cell, in
+// the order of m.free, or -1 where food cannot be reached.
+func (m *Maze) distances() []int {
+	dist := make([][]int, m.Height)
+	for y := range dist {
+		dist[y] = make([]int, m.Width)
+	}
+	var queue [][2]int
+	for y, row := range m.cells {
+		for x, c := range row {
+			dist[y][x] = -1
+			if isFood(c) {
+				dist[y][x] = 0
+				queue = append(queue, [2]int{x, y})
+			}
+		}
+	}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for _, d := range directions {
+			x := ((p[0]+d[0])%m.Width + m.Width) % m.Width
+			y := ((p[1]+d[1])%m.Height + m.Height) % m.Height
+			if dist[y][x] == -1 && m.cells[y][x] == Empty {
+				dist[y][x] = dist[p[1]][p[0]] + 1
+				queue = append(queue, [2]int{x, y})
+			}
+		}
+	}
+	result := make([]int, len(m.free))
+	for i, p := range m.free {
+		result[i] = dist[p[1]][p[0]]
+	}
+	return result
+}
+
+// OptimalSteps returns the mean number of steps to food from the empty
+// cells, the best average an animat can achieve.
+func (m *Maze) OptimalSteps() float64 {
+	sum := 0
+	for _, d := range m.distances() {
+		sum += d
+	}
+	return float64(sum) / float64(len(m.free))
+}
+
+func (m *Maze) IsAtEndState() bool {
+	return m.EndState
+}
+
+// Truncated reports whether the episode ended at the step limit rather
+// than at food.
+func (m *Maze) Truncated() bool {
+	return m.EndState && !isFood(m.cell(m.X, m.Y))
+}
+
+// Reset places the animat on a random empty cell.
+func (m *Maze) Reset() {
+	p := m.free[m.rnd.Intn(len(m.free))]
+	m.X, m.Y = p[0], p[1]
+	m.Steps = 0
+	m.EndState = false
+}
+
+// ObtainInput returns the codes of the eight cells around the animat.
+func (m *Maze) ObtainInput() mli.DataItem {
+	inputs := make([]int, 0, 8*m.bits)
+	for _, d := range directions {
+		for _, b := range codes[m.bits][m.cell(m.X+d[0], m.Y+d[1])] {
+			inputs = append(inputs, int(b-'0'))
+		}
+	}
+	return &Percept{inputs}
+}
+
+// Observe returns the same as ObtainInput: the percept of the cell the
+// animat is on, which after Effect is the cell the move led to.
+func (m *Maze) Observe() mli.DataItem {
+	return m.ObtainInput()
+}
+
+// Metric returns mli.MetricSteps, the mean number of steps to food.
+func (m *Maze) Metric() mli.Metric {
+	return mli.MetricSteps
+}
+
+// Effect moves the animat in the direction of the action unless an
+// obstacle is in the way. It returns FoodReward on reaching food and 0
+// otherwise; the episode ends at food or at the step limit.
+func (m *Maze) Effect(action int) int {
+	m.Steps++
+	reward := 0
+	if action >= 0 && action < len(directions) {
+		x, y := m.X+directions[action][0], m.Y+directions[action][1]
+		c := m.cell(x, y)
+		if !isObstacle(c) {
+			m.X = (x%m.Width + m.Width) % m.Width
+			m.Y = (y%m.Height + m.Height) % m.Height
+		}
+		if isFood(c) {
+			reward = FoodReward
+			m.EndState = true
+		}
+	}
+	if m.Steps >= m.StepLimit {
+		m.EndState = true
+	}
+	return reward
+}
+
+// Percept is the input of a maze: the code of each neighbouring cell,
+// starting north and going clockwise.
+type Percept struct {
+	Inputs []int
+}
+
+func (p *Percept) GetInputs() []int {
+	return p.Inputs
+}
+
+// GetAnswer returns -1: a maze has no single correct action.
+func (p *Percept) GetAnswer() int {
+	return -1
+}
+
+func (p *Percept) GetAttribute(n int) int {
+	return p.Inputs[n]
+}
+
+func (p *Percept) ToString() string {
+	builder := strings.Builder{}
+	for _, v := range p.Inputs {
+		builder.WriteString(strconv.Itoa(v))
+	}
+	return builder.String()
+}
@@ -6,3 +6,11 @@ type DataItem interface {
 	GetAttribute(n int) int
 	ToString() string
 }
+
+// RealDataItem is a DataItem with real-valued inputs. GetInputs returns
+// the inputs rounded or thresholded by the problem; algorithms with
+// real-valued conditions use GetRealInputs instead.
+type RealDataItem interface {
+	DataItem
+	GetRealInputs() []float64
+}
@@ -6,3 +6,68 @@ type Problem interface {
 	ObtainInput() DataItem
 	Effect(action int) int
 }
+
+// RealRewardProblem is a Problem whose reward is real-valued, such as the
+// target of a function approximation problem. Algorithms that support it
+// call RealEffect in place of Effect.
+type RealRewardProblem interface {
+	Problem
+	RealEffect(action int) float64
+}
+
+// ObservableProblem is a Problem whose current state can be observed
+// without changing it. ObtainInput may move a problem on, as drawing a
+// new data item does; Observe never does, so after ObtainInput and
+// Effect it returns the state the action led to. Algorithms that learn
+// what their actions do, such as anticipatory classifier systems,
+// require it.
+type ObservableProblem interface {
+	Problem
+	Observe() DataItem
+}
+
+// TruncatedProblem is a Problem whose episodes may be cut off before
+// they end, as a maze episode is at its step limit. Truncated reports
+// whether the episode at its end state was cut off; learners do not take
+// the last reward of such an episode as its final payoff.
+type TruncatedProblem interface {
+	Problem
+	Truncated() bool
+}
+
+// Metric names a measure of performance on a problem.
+type Metric string
+
+const (
+	// MetricAccuracy is the proportion of episodes answered correctly.
+	MetricAccuracy Metric = "accuracy"
+	// MetricSteps is the mean number of steps taken to reach the goal.
+	MetricSteps Metric = "steps"
+	// MetricReward is the mean total reward received per episode.
+	MetricReward Metric = "reward"
+	// MetricSystemError is the mean error of the predicted payoff.
+	MetricSystemError Metric = "systemError"
+)
+
+// MetricProblem is a Problem that advertises the metric by which
+// performance on it is best judged. Problems that do not implement it
+// are judged by accuracy.
+type MetricProblem interface {
+	Problem
+	Metric() Metric
+}
+
+// Description returns the name of the metric as printed in reports.
+func (m Metric) Description() string {
+	switch m {
+	case MetricAccuracy:
+		return "Proportion correct"
+	case MetricSteps:
+		return "Average steps"
+	case MetricReward:
+		return "Average reward"
+	case MetricSystemError:
+		return "System error"
+	}
+	return string(m)
+}
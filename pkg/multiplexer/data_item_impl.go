@@ -31,3 +31,22 @@ func (d *DataItemImpl) GetAnswer() int {
 func (d *DataItemImpl) GetAttribute(n int) int {
 	return d.Inputs[n]
 }
+
+// RealDataItemImpl is a data item of the real-valued multiplexer. Inputs
+// holds the thresholded values.
+type RealDataItemImpl struct {
+	DataItemImpl
+	Values []float64
+}
+
+func (d *RealDataItemImpl) ToString() string {
+	parts := make([]string, len(d.Values))
+	for i, v := range d.Values {
+		parts[i] = strconv.FormatFloat(v, 'f', 3, 64)
+	}
+	return strings.Join(parts, " ") + " --> " + strconv.Itoa(d.Answer)
+}
+
+func (d *RealDataItemImpl) GetRealInputs() []float64 {
+	return d.Values
+}
@@ -3,22 +3,32 @@
 package multiplexer
 
 import (
-	"log"
+	"errors"
+	"fmt"
 	"math"
-	"math/rand"
 
 	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
 )
 
+// ErrInvalidSize is returned by New when the size is not k + 2^k for
+// some number of control bits k.
+var ErrInvalidSize = errors.New("invalid multiplexer size")
+
 type Multiplexer struct {
 	MultiplexerSize int
 	ControlBits     int
 	CorrectAnswer   int
 	LastAnswer      int
 	EndState        bool
+	Real            bool
+	rnd             rng.Generator
+	current         mli.DataItem
 }
 
-func New(multiplexerSize int) *Multiplexer {
+// New returns a multiplexer of the given size that draws its inputs from
+// rnd.
+func New(multiplexerSize int, rnd rng.Generator) (*Multiplexer, error) {
 	controlBits := -1
 	for k := 1; k < multiplexerSize; k++ {
 		maxNum := int(math.Pow(2, float64(k)))
@@ -27,9 +37,21 @@ func New(multiplexerSize int) *Multiplexer {
 		}
 	}
 	if controlBits == -1 {
-		log.Fatalf("%v bits is not a valid multiplexer", multiplexerSize)
+		return nil, fmt.Errorf("%w: %v bits is not a valid multiplexer", ErrInvalidSize, multiplexerSize)
+	}
+	return &Multiplexer{multiplexerSize, controlBits, -1, -1, false, false, rnd, nil}, nil
+}
+
+// NewReal returns a real-valued multiplexer: each input is drawn
+// uniformly from [0, 1) and read as 1 if it is at least 0.5 and as 0
+// otherwise.
+func NewReal(multiplexerSize int, rnd rng.Generator) (*Multiplexer, error) {
+	m, err := New(multiplexerSize, rnd)
+	if err != nil {
+		return nil, err
 	}
-	return &Multiplexer{multiplexerSize, controlBits, -1, -1, false}
+	m.Real = true
+	return m, nil
 }
 
 func (m *Multiplexer) IsAtEndState() bool {
@@ -41,12 +63,42 @@ func (m *Multiplexer) Reset() {
 }
 
 func (m *Multiplexer) ObtainInput() mli.DataItem {
+	if m.Real {
+		return m.obtainRealInput()
+	}
+	attributes := make([]int, m.MultiplexerSize)
+	for j := 0; j < m.MultiplexerSize; j++ {
+		attributes[j] = m.rnd.Intn(2)
+	}
+	m.CorrectAnswer = m.GetMultiplexerAnswer(attributes)
+	m.current = &DataItemImpl{attributes, m.CorrectAnswer}
+	return m.current
+}
+
+func (m *Multiplexer) obtainRealInput() mli.DataItem {
+	values := make([]float64, m.MultiplexerSize)
 	attributes := make([]int, m.MultiplexerSize)
 	for j := 0; j < m.MultiplexerSize; j++ {
-		attributes[j] = rand.Intn(2)
+		values[j] = m.rnd.Float64()
+		if values[j] >= 0.5 {
+			attributes[j] = 1
+		}
 	}
 	m.CorrectAnswer = m.GetMultiplexerAnswer(attributes)
-	return &DataItemImpl{attributes, m.CorrectAnswer}
+	m.current = &RealDataItemImpl{DataItemImpl{attributes, m.CorrectAnswer}, values}
+	return m.current
+}
+
+// Observe returns the data item last obtained, which an action does not
+// change, or nil before the first.
+func (m *Multiplexer) Observe() mli.DataItem {
+	return m.current
+}
+
+// Metric returns mli.MetricAccuracy: every episode is a single
+// classification.
+func (m *Multiplexer) Metric() mli.Metric {
+	return mli.MetricAccuracy
 }
 
 func (m *Multiplexer) Effect(action int) int {
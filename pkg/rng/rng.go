@@ -0,0 +1,56 @@
+// Package rng provides a random number generator whose complete state is
+// a single 64-bit word, so that it can be captured in a checkpoint and
+// restored later to continue the same sequence.
+package rng
+
+import "math/rand"
+
+// Source is a SplitMix64 generator implementing rand.Source64.
+type Source struct {
+	state uint64
+}
+
+func (s *Source) Seed(seed int64) {
+	s.state = uint64(seed)
+}
+
+func (s *Source) Uint64() uint64 {
+	s.state += 0x9e3779b97f4a7c15
+	z := s.state
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
+
+func (s *Source) Int63() int64 {
+	return int64(s.Uint64() >> 1)
+}
+
+// Rand is a rand.Rand backed by a Source. The methods of rand.Rand other
+// than Read keep no state of their own, so State and SetState capture
+// everything needed to reproduce the sequence.
+type Rand struct {
+	*rand.Rand
+	src *Source
+}
+
+func New(seed int64) *Rand {
+	src := &Source{}
+	src.Seed(seed)
+	return &Rand{rand.New(src), src}
+}
+
+func (r *Rand) State() uint64 {
+	return r.src.state
+}
+
+func (r *Rand) SetState(state uint64) {
+	r.src.state = state
+}
+
+// Generator is the subset of rand.Rand used by problems. Both *rand.Rand
+// and *Rand satisfy it.
+type Generator interface {
+	Float64() float64
+	Intn(n int) int
+}
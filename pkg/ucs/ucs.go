@@ -0,0 +1,223 @@
+// Package ucs provides the sUpervised Classifier System (UCS) of
+// Bernadó-Mansilla and Garrell-Guiu (2003), with the fitness sharing of
+// Orriols-Puig and Bernadó-Mansilla (2008). UCS learns from the answer
+// of each data item rather than from a reward: the classifiers of the
+// match set that advocate the answer form the correct set, accuracy is
+// the proportion of matches in which a classifier was in the correct
+// set, and the genetic algorithm runs on the correct set.
+//
+// UCS reuses the classifiers, conditions, population, genetic algorithm
+// and deletion of package xcs. A classifier's Payoff holds its accuracy
+// and its PredictionError the complementary error rate, so that
+// populations evolved by UCS can be saved and inspected like any other.
+// Classes are predicted by Vote, which xcs.Model also uses once given it
+// with SetPredictionFunc.
+package ucs
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// ErrNoAnswer is returned when a data item has no answer within the
+// range of actions, as is the case for multi-step problems.
+var ErrNoAnswer = errors.New("data item has no answer")
+
+type Ucs struct {
+	x        *xcs.Xcs
+	params   xcs.Params
+	schedule xcs.Schedule
+	observer xcs.Observer
+}
+
+// DefaultParams returns the parameters of Orriols-Puig and
+// Bernadó-Mansilla (2008). ErrorZero is the highest error rate of an
+// accurate classifier, 1 - acc0 in the UCS literature, and Nu the power
+// to which relative accuracy is raised below it.
+func DefaultParams() xcs.Params {
+	p := xcs.DefaultParams()
+	p.ErrorZero = 0.01
+	p.Nu = 10
+	p.ThetaGa = 25
+	p.FitnessI = 0.01
+	p.InitialError = 0
+	return p
+}
+
+// New returns a UCS that draws all of its random numbers from rnd.
+func New(params xcs.Params, rnd *rng.Rand) (*Ucs, error) {
+	if params.ErrorZero >= 1 {
+		return nil, xcs.InvalidParam("errorZero", params.ErrorZero, "must be below 1 for UCS, where it is the highest error rate of an accurate classifier")
+	}
+	x, err := xcs.New(params, rnd)
+	if err != nil {
+		return nil, err
+	}
+	x.SetPredictionFunc(Vote)
+	u := &Ucs{x: x, params: params, schedule: xcs.DefaultSchedule()}
+	u.SetObserver(&xcs.ConsoleObserver{Writer: os.Stdout, PrintPopulation: true})
+	return u, nil
+}
+
+// SetObserver replaces the observer notified of run events; nil
+// silences the run.
+func (u *Ucs) SetObserver(observer xcs.Observer) {
+	if observer == nil {
+		observer = xcs.NopObserver{}
+	}
+	u.observer = observer
+	u.x.SetObserver(observer)
+}
+
+func (u *Ucs) GetParams() xcs.Params {
+	return u.params
+}
+
+func (u *Ucs) SetSchedule(schedule xcs.Schedule) error {
+	if err := u.x.SetSchedule(schedule); err != nil {
+		return err
+	}
+	u.schedule = schedule
+	return nil
+}
+
+// Assess evaluates the population on samples data items, predicting
+// each class by Vote; see xcs.Xcs.Assess.
+func (u *Ucs) Assess(problem mli.Problem, ruleSet *xcs.Population, samples int) (xcs.Assessment, error) {
+	return u.x.Assess(problem, ruleSet, samples)
+}
+
+// OperateOn trains on the problem; see xcs.Xcs.OperateOn.
+func (u *Ucs) OperateOn(problem mli.Problem) {
+	if _, err := u.Train(problem); err != nil {
+		log.Print(err)
+	}
+}
+
+// Train runs the configured number of iterations on the problem and
+// returns the evolved population. Each iteration learns from one data
+// item and then performs the predicted class, so that the problem moves
+// on and the reward can be reported.
+func (u *Ucs) Train(problem mli.Problem) (*xcs.Population, error) {
+	ruleSet := xcs.NewPopulation()
+	for i := 0; i < u.schedule.Iterations; i++ {
+		reward, err := u.learn(problem, ruleSet, int64(i))
+		if err != nil {
+			return nil, fmt.Errorf("iteration %v: %w", i, err)
+		}
+		u.observer.IterationFinished(xcs.IterationEvent{Iteration: i, Steps: 1, TotalReward: reward, MacroClassifiers: ruleSet.Len()})
+		if u.schedule.EvalInterval > 0 && i != 0 && i%u.schedule.EvalInterval == 0 {
+			if err := u.x.Evaluate(problem, ruleSet, i); err != nil {
+				return nil, fmt.Errorf("evaluation after iteration %v: %w", i, err)
+			}
+		}
+	}
+	u.observer.TrainingFinished(xcs.TrainingEvent{Iterations: u.schedule.Iterations, Population: ruleSet})
+	return ruleSet, nil
+}
+
+func (u *Ucs) learn(problem mli.Problem, ruleSet *xcs.Population, step int64) (float64, error) {
+	problem.Reset()
+	dataItem := problem.ObtainInput()
+	answer := dataItem.GetAnswer()
+	if answer < 0 || answer > u.params.MaxAction {
+		return 0, fmt.Errorf("%w: %v gives %v", ErrNoAnswer, dataItem.ToString(), answer)
+	}
+	matchSet, err := u.x.ObtainMatchingClassifiers(ruleSet, dataItem)
+	if err != nil {
+		return 0, err
+	}
+	correctSet := u.x.CreateActionSet(matchSet, answer)
+	if len(correctSet) == 0 {
+		cl, err := u.x.CoverAction(dataItem, answer, step)
+		if err != nil {
+			return 0, err
+		}
+		ruleSet.Add(cl)
+		u.observer.CoveringOccurred(xcs.CoveringEvent{Step: step, Classifier: cl})
+		u.x.DeleteFromPop(ruleSet)
+		matchSet = append(matchSet, cl)
+		correctSet = append(correctSet, cl)
+	}
+	action := predict(u.x.CreatePredictionArray(matchSet, dataItem))
+	u.UpdateSets(matchSet, correctSet, ruleSet)
+	if u.params.ActionSetSubsumption {
+		correctSet = u.x.DoActionSetSubsumption(correctSet, ruleSet)
+	}
+	if err := u.x.RunGeneticAlgorithm(correctSet, dataItem, ruleSet, step); err != nil {
+		return 0, err
+	}
+	return float64(problem.Effect(action)), nil
+}
+
+// UpdateSets updates the experience and accuracy of the classifiers of
+// the match set, the niche size of those in the correct set, and the
+// fitness of all of them. Fitness is shared among the correct set in
+// proportion to numerosity and accuracy; the other classifiers of the
+// match set see their fitness decay.
+func (u *Ucs) UpdateSets(matchSet, correctSet []*xcs.Classifier, ruleSet *xcs.Population) {
+	beta := u.params.Beta
+	correct := make(map[*xcs.Classifier]bool, len(correctSet))
+	for _, cl := range correctSet {
+		correct[cl] = true
+	}
+	correctSetSize := u.x.CountMicroClassifiers(correctSet)
+	k := make([]float64, len(matchSet))
+	accuracySum := 0.0
+	for i, cl := range matchSet {
+		cl.IncrementExperience()
+		hit := 0.0
+		if correct[cl] {
+			hit = 1.0
+			cl.UpdateCorrectSetSize(correctSetSize, beta)
+		}
+		accuracy := cl.GetPayoff() + (hit-cl.GetPayoff())/float64(cl.GetExperience())
+		cl.SetPayoff(accuracy)
+		cl.SetPredictionError(1 - accuracy)
+		if !correct[cl] {
+			continue
+		}
+		if 1-accuracy < u.params.ErrorZero {
+			k[i] = 1.0
+		} else {
+			k[i] = u.params.Alpha * math.Pow(accuracy/(1-u.params.ErrorZero), u.params.Nu)
+		}
+		accuracySum += k[i] * float64(cl.GetNumerosity())
+	}
+	for i, cl := range matchSet {
+		fitness := cl.GetFitness() + beta*(k[i]*float64(cl.GetNumerosity())/accuracySum-cl.GetFitness())
+		ruleSet.SetFitness(cl, fitness)
+	}
+}
+
+// Vote returns the support of each class advocated in the match set:
+// the sum of the products of accuracy and fitness of the classifiers
+// advocating it. Unlike the fitness-weighted average of XCS, it lets a
+// well-established niche outvote young classifiers that inherited the
+// accuracy of their parents.
+func Vote(matchSet []*xcs.Classifier, dataItem mli.DataItem) map[int]float64 {
+	votes := make(map[int]float64)
+	for _, cl := range matchSet {
+		votes[cl.GetAction()] += cl.GetPayoff() * cl.GetFitness()
+	}
+	return votes
+}
+
+// predict returns the class with the most support, preferring the
+// lowest-numbered class on ties.
+func predict(votes map[int]float64) int {
+	best := -1
+	for a, v := range votes {
+		if best == -1 || v > votes[best] || (v == votes[best] && a < best) {
+			best = a
+		}
+	}
+	return best
+}
@@ -0,0 +1,46 @@
+package ucs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/ucs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+func TestLearnsMultiplexer(t *testing.T) {
+	rnd := rng.New(1)
+	problem, err := multiplexer.New(6, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := ucs.DefaultParams()
+	params.MaxPop = 400
+	u, err := ucs.New(params, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.SetObserver(xcs.NopObserver{})
+	if err := u.SetSchedule(xcs.Schedule{Iterations: 5000}); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := u.Train(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := u.Assess(problem, ruleSet, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ProportionCorrect < 0.95 {
+		t.Errorf("proportion correct after 5000 iterations = %v, want at least 0.95", a.ProportionCorrect)
+	}
+}
+
+func TestRejectsXcsErrorZero(t *testing.T) {
+	if _, err := ucs.New(xcs.DefaultParams(), rng.New(1)); !errors.Is(err, xcs.ErrInvalidParam) {
+		t.Errorf("New with errorZero = 10 returned %v", err)
+	}
+}
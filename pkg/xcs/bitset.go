@@ -0,0 +1,218 @@
+package xcs
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+)
+
+// BitsetCondition is a ternary condition stored as two bitsets: care has
+// a bit set for each "0" or "1" symbol and value a bit set for each "1".
+// Matching, comparison and subsumption work on 64 symbols at a time.
+// Covering, mutation and crossover draw the same random numbers as
+// TernaryCondition, so the two give identical runs.
+type BitsetCondition struct {
+	n     int
+	care  []uint64
+	value []uint64
+}
+
+func newBitset(n int) BitsetCondition {
+	words := (n + 63) / 64
+	return BitsetCondition{n, make([]uint64, words), make([]uint64, words)}
+}
+
+// ParseBitset reads a condition written as a string of 0, 1 and #
+// symbols.
+func ParseBitset(s string) (BitsetCondition, error) {
+	t, err := ParseTernary(s)
+	if err != nil {
+		return BitsetCondition{}, err
+	}
+	b := newBitset(len(t))
+	for i, symbol := range t {
+		if symbol != "#" {
+			b.set(i, symbol == "1")
+		}
+	}
+	return b, nil
+}
+
+func (b BitsetCondition) set(i int, one bool) {
+	w, m := i/64, uint64(1)<<uint(i%64)
+	b.care[w] |= m
+	if one {
+		b.value[w] |= m
+	} else {
+		b.value[w] &^= m
+	}
+}
+
+func (b BitsetCondition) clear(i int) {
+	w, m := i/64, uint64(1)<<uint(i%64)
+	b.care[w] &^= m
+	b.value[w] &^= m
+}
+
+func (b BitsetCondition) cares(i int) bool {
+	return b.care[i/64]&(uint64(1)<<uint(i%64)) != 0
+}
+
+func (b BitsetCondition) isOne(i int) bool {
+	return b.value[i/64]&(uint64(1)<<uint(i%64)) != 0
+}
+
+// packedInput carries the inputs of a data item packed into words, so
+// that they are packed once per match set rather than once per
+// classifier.
+type packedInput struct {
+	mli.DataItem
+	bits []uint64
+}
+
+func packInput(dataItem mli.DataItem) *packedInput {
+	inputs := dataItem.GetInputs()
+	p := &packedInput{dataItem, make([]uint64, (len(inputs)+63)/64)}
+	for i, v := range inputs {
+		if v != 0 {
+			p.bits[i/64] |= uint64(1) << uint(i%64)
+		}
+	}
+	return p
+}
+
+func (b BitsetCondition) Matches(dataItem mli.DataItem) bool {
+	p, ok := dataItem.(*packedInput)
+	if !ok {
+		p = packInput(dataItem)
+	}
+	for w := range b.care {
+		if (p.bits[w]^b.value[w])&b.care[w] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Cover copies the inputs, replacing each by '#' with probability PHash.
+// It fails with ErrInvalidInput for inputs other than 0 and 1.
+func (b BitsetCondition) Cover(dataItem mli.DataItem, p *Params, rnd rng.Generator) (Condition, error) {
+	inputs := dataItem.GetInputs()
+	condition := newBitset(len(inputs))
+	for i, attrib := range inputs {
+		if attrib != 0 && attrib != 1 {
+			return nil, fmt.Errorf("%w: attribute %v of %v is %v, expected 0 or 1", ErrInvalidInput, i, dataItem.ToString(), attrib)
+		}
+		if rnd.Float64() >= p.PHash {
+			condition.set(i, attrib == 1)
+		}
+	}
+	return condition, nil
+}
+
+// Mutate changes each symbol with probability Mu as set out by
+// mutateSymbol.
+func (b BitsetCondition) Mutate(dataItem mli.DataItem, p *Params, rnd rng.Generator) {
+	for k := 0; k < b.n; k++ {
+		if rnd.Float64() < p.Mu {
+			value := 0
+			if b.isOne(k) {
+				value = 1
+			}
+			if specified, value := mutateSymbol(b.cares(k), value, dataItem.GetAttribute(k), p, rnd); specified {
+				b.set(k, value == 1)
+			} else {
+				b.clear(k)
+			}
+		}
+	}
+}
+
+// Crossover swaps the symbols chosen by CrossoverMask.
+func (b BitsetCondition) Crossover(other Condition, p *Params, rnd rng.Generator) {
+	o, ok := other.(BitsetCondition)
+	if !ok || o.n != b.n {
+		return
+	}
+	masks := make([]uint64, len(b.care))
+	for k, swap := range CrossoverMask(b.n, p, rnd) {
+		if swap {
+			masks[k/64] |= 1 << uint(k%64)
+		}
+	}
+	for w, mask := range masks {
+		if mask == 0 {
+			continue
+		}
+		b.care[w], o.care[w] = b.care[w]&^mask|o.care[w]&mask, o.care[w]&^mask|b.care[w]&mask
+		b.value[w], o.value[w] = b.value[w]&^mask|o.value[w]&mask, o.value[w]&^mask|b.value[w]&mask
+	}
+}
+
+func (b BitsetCondition) IsMoreGeneralThan(other Condition) bool {
+	o, ok := other.(BitsetCondition)
+	if !ok || b.n != o.n || b.specificity() >= o.specificity() {
+		return false
+	}
+	for w := range b.care {
+		if b.care[w]&^o.care[w] != 0 || (b.value[w]^o.value[w])&b.care[w] != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (b BitsetCondition) specificity() int {
+	count := 0
+	for _, w := range b.care {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// Generality returns the proportion of '#' symbols.
+func (b BitsetCondition) Generality() float64 {
+	if b.n == 0 {
+		return 0
+	}
+	return float64(b.n-b.specificity()) / float64(b.n)
+}
+
+func (b BitsetCondition) Equal(other Condition) bool {
+	o, ok := other.(BitsetCondition)
+	if !ok || b.n != o.n {
+		return false
+	}
+	for w := range b.care {
+		if b.care[w] != o.care[w] || b.value[w] != o.value[w] {
+			return false
+		}
+	}
+	return true
+}
+
+func (b BitsetCondition) String() string {
+	builder := strings.Builder{}
+	for i := 0; i < b.n; i++ {
+		switch {
+		case !b.cares(i):
+			builder.WriteByte('#')
+		case b.isOne(i):
+			builder.WriteByte('1')
+		default:
+			builder.WriteByte('0')
+		}
+	}
+	return builder.String()
+}
+
+func (b BitsetCondition) Len() int {
+	return b.n
+}
+
+func (b BitsetCondition) Copy() Condition {
+	return BitsetCondition{b.n, append([]uint64(nil), b.care...), append([]uint64(nil), b.value...)}
+}
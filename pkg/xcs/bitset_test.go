@@ -0,0 +1,264 @@
+package xcs_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// randomTernary returns a condition of n symbols with about a third of
+// them '#'.
+func randomTernary(n int, rnd *rng.Rand) string {
+	var builder strings.Builder
+	for i := 0; i < n; i++ {
+		switch rnd.Intn(3) {
+		case 0:
+			builder.WriteString("#")
+		case 1:
+			builder.WriteString("0")
+		default:
+			builder.WriteString("1")
+		}
+	}
+	return builder.String()
+}
+
+// generalize replaces about half of the specified symbols of s with '#'.
+func generalize(s string, rnd *rng.Rand) string {
+	symbols := []byte(s)
+	for i := range symbols {
+		if rnd.Intn(2) == 0 {
+			symbols[i] = '#'
+		}
+	}
+	return string(symbols)
+}
+
+// conditionPair parses s as both representations.
+func conditionPair(t testing.TB, s string) (xcs.TernaryCondition, xcs.BitsetCondition) {
+	t.Helper()
+	ternary, err := xcs.ParseTernary(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bitset, err := xcs.ParseBitset(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ternary, bitset
+}
+
+func randomInputs(t testing.TB, size, count int, rnd *rng.Rand) []mli.DataItem {
+	t.Helper()
+	problem, err := multiplexer.New(size, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs := make([]mli.DataItem, count)
+	for i := range inputs {
+		inputs[i] = problem.ObtainInput()
+	}
+	return inputs
+}
+
+func TestBitsetAgreesWithTernary(t *testing.T) {
+	rnd := rng.New(1)
+	params := xcs.DefaultParams()
+	for _, size := range []int{6, 11, 70, 135} {
+		inputs := randomInputs(t, size, 50, rnd)
+		for i := 0; i < 200; i++ {
+			s := randomTernary(size, rnd)
+			other := s
+			if i%2 == 0 {
+				other = generalize(s, rnd)
+			} else if i%4 == 1 {
+				other = randomTernary(size, rnd)
+			}
+			t1, b1 := conditionPair(t, s)
+			t2, b2 := conditionPair(t, other)
+			if b1.String() != s {
+				t.Fatalf("bitset %v reads back as %v", s, b1.String())
+			}
+			for _, input := range inputs {
+				if t1.Matches(input) != b1.Matches(input) {
+					t.Errorf("%v on %v: ternary match %v, bitset %v", s, input.ToString(), t1.Matches(input), b1.Matches(input))
+				}
+			}
+			if t2.IsMoreGeneralThan(t1) != b2.IsMoreGeneralThan(b1) || t1.IsMoreGeneralThan(t2) != b1.IsMoreGeneralThan(b2) {
+				t.Errorf("%v and %v: IsMoreGeneralThan differs", s, other)
+			}
+			if t1.Equal(t2) != b1.Equal(b2) {
+				t.Errorf("%v and %v: Equal differs", s, other)
+			}
+			if t1.Generality() != b1.Generality() {
+				t.Errorf("%v: generality %v, bitset %v", s, t1.Generality(), b1.Generality())
+			}
+
+			// Mutation and crossover draw the same random numbers.
+			seed := int64(i)
+			t1.Mutate(inputs[0], &params, rng.New(seed))
+			b1.Mutate(inputs[0], &params, rng.New(seed))
+			t1.Crossover(t2, &params, rng.New(seed))
+			b1.Crossover(b2, &params, rng.New(seed))
+			if t1.String() != b1.String() || t2.String() != b2.String() {
+				t.Errorf("%v and %v: offspring %v and %v, bitset %v and %v", s, other, t1, t2, b1, b2)
+			}
+		}
+	}
+}
+
+func TestBitsetTrainsLikeTernary(t *testing.T) {
+	var rules [2][]string
+	for i, condition := range []string{xcs.ConditionTernary, xcs.ConditionBitset} {
+		params := xcs.DefaultParams()
+		params.Condition = condition
+		for _, cl := range trainMultiplexer(t, params, 3000).Classifiers() {
+			rules[i] = append(rules[i], cl.ToString())
+		}
+	}
+	if strings.Join(rules[0], "\n") != strings.Join(rules[1], "\n") {
+		t.Errorf("bitset run evolved a different population")
+	}
+}
+
+func TestDeprecatedConditionAccessors(t *testing.T) {
+	ternary, bitset := conditionPair(t, "0#1#")
+	for _, condition := range []xcs.Condition{ternary, bitset} {
+		cl := &xcs.Classifier{Condition: condition}
+		if cl.GetHashCount() != 2 {
+			t.Errorf("%T: hash count %v, want 2", condition, cl.GetHashCount())
+		}
+		cl.SetConditionComponent(0, "#")
+		cl.SetConditionComponent(1, "1")
+		cl.SetConditionComponent(2, "0")
+		if s := cl.GetCondition().String(); s != "#10#" {
+			t.Errorf("%T: condition became %v, want #10#", condition, s)
+		}
+	}
+}
+
+var benchSizes = []int{6, 11, 70}
+
+// benchmarkConditions runs f for both representations of random
+// conditions at each benchmark size.
+func benchmarkConditions(b *testing.B, f func(b *testing.B, conditions []xcs.Condition, inputs []mli.DataItem)) {
+	for _, size := range benchSizes {
+		rnd := rng.New(1)
+		inputs := randomInputs(b, size, 256, rnd)
+		var ternary, bitset []xcs.Condition
+		for i := 0; i < 256; i++ {
+			s := randomTernary(size, rnd)
+			if i%2 == 1 {
+				s = generalize(s, rnd)
+			}
+			t, bs := conditionPair(b, s)
+			ternary = append(ternary, t)
+			bitset = append(bitset, bs)
+		}
+		b.Run(fmt.Sprintf("ternary/%v", size), func(b *testing.B) { f(b, ternary, inputs) })
+		b.Run(fmt.Sprintf("bitset/%v", size), func(b *testing.B) { f(b, bitset, inputs) })
+	}
+}
+
+func BenchmarkMatches(b *testing.B) {
+	benchmarkConditions(b, func(b *testing.B, conditions []xcs.Condition, inputs []mli.DataItem) {
+		for i := 0; i < b.N; i++ {
+			conditions[i%len(conditions)].Matches(inputs[i%len(inputs)])
+		}
+	})
+}
+
+func BenchmarkDoesMatch(b *testing.B) {
+	benchmarkConditions(b, func(b *testing.B, conditions []xcs.Condition, inputs []mli.DataItem) {
+		classifiers := make([]*xcs.Classifier, len(conditions))
+		for i, c := range conditions {
+			classifiers[i] = &xcs.Classifier{Condition: c}
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			classifiers[i%len(classifiers)].DoesMatch(classifiers[(i+1)%len(classifiers)])
+		}
+	})
+}
+
+func BenchmarkIsMoreGeneralThan(b *testing.B) {
+	benchmarkConditions(b, func(b *testing.B, conditions []xcs.Condition, inputs []mli.DataItem) {
+		for i := 0; i < b.N; i++ {
+			// Odd conditions are generalized from the even ones before.
+			j := i % len(conditions) &^ 1
+			conditions[j+1].IsMoreGeneralThan(conditions[j])
+		}
+	})
+}
+
+// BenchmarkMatchSet forms match sets from 2000 covered rules, packing
+// each input once for bitset conditions as training does.
+func BenchmarkMatchSet(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, condition := range []string{xcs.ConditionTernary, xcs.ConditionBitset} {
+			rnd := rng.New(1)
+			params := xcs.DefaultParams()
+			params.Condition = condition
+			x, err := xcs.New(params, rnd)
+			if err != nil {
+				b.Fatal(err)
+			}
+			inputs := randomInputs(b, size, 2256, rnd)
+			var prototype xcs.Condition = xcs.TernaryCondition(nil)
+			if condition == xcs.ConditionBitset {
+				prototype = xcs.BitsetCondition{}
+			}
+			ruleSet := xcs.NewPopulation()
+			for _, input := range inputs[256:] {
+				c, err := prototype.Cover(input, &params, rnd)
+				if err != nil {
+					b.Fatal(err)
+				}
+				ruleSet.Add(&xcs.Classifier{Condition: c})
+			}
+			inputs = inputs[:256]
+			b.Run(fmt.Sprintf("%v/%v", condition, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := x.ObtainMatchingClassifiers(ruleSet, inputs[i%len(inputs)]); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkTrain runs 1000 training iterations on the multiplexer.
+func BenchmarkTrain(b *testing.B) {
+	for _, size := range benchSizes {
+		for _, condition := range []string{xcs.ConditionTernary, xcs.ConditionBitset} {
+			b.Run(fmt.Sprintf("%v/%v", condition, size), func(b *testing.B) {
+				params := xcs.DefaultParams()
+				params.Condition = condition
+				for i := 0; i < b.N; i++ {
+					rnd := rng.New(int64(i))
+					problem, err := multiplexer.New(size, rnd)
+					if err != nil {
+						b.Fatal(err)
+					}
+					x, err := xcs.New(params, rnd)
+					if err != nil {
+						b.Fatal(err)
+					}
+					x.SetObserver(xcs.NopObserver{})
+					if err := x.SetSchedule(xcs.Schedule{Iterations: 1000}); err != nil {
+						b.Fatal(err)
+					}
+					if _, err := x.Train(problem); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
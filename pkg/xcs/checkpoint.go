@@ -0,0 +1,144 @@
+package xcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+)
+
+// CheckpointVersion is the version written by WriteCheckpoint.
+const CheckpointVersion = 3
+
+// Checkpoint captures a training run between two iterations: the
+// population, the step counters and the state of the random number
+// generator. Checkpoints are only taken between episodes, when the
+// multi-step bookkeeping (the previous action set and reward) has been
+// cleared, so it does not need to be stored. Resuming from a checkpoint
+// continues the run exactly as if it had not been interrupted, provided
+// the problem is reset in the same way.
+type Checkpoint struct {
+	Version              int                `json:"version"`
+	Params               Params             `json:"params"`
+	Iteration            int                `json:"iteration"`
+	CumulativeMicroSteps int64              `json:"cumulativeMicroSteps"`
+	MacroStep            int                `json:"macroStep"`
+	RandState            uint64             `json:"randState"`
+	Classifiers          []classifierRecord `json:"classifiers"`
+}
+
+type trainingState struct {
+	ruleSet              *Population
+	iteration            int
+	cumulativeMicroSteps int64
+	macroStep            int
+}
+
+// SetCheckpointHandler registers a function that receives a checkpoint
+// every Schedule.CheckpointInterval iterations. An error returned by the
+// handler stops training and is returned by Train or Resume.
+func (x *Xcs) SetCheckpointHandler(handler func(cp *Checkpoint) error) {
+	x.onCheckpoint = handler
+}
+
+func (x *Xcs) checkpoint(state *trainingState) *Checkpoint {
+	records := make([]classifierRecord, 0, state.ruleSet.Len())
+	for _, cl := range state.ruleSet.Classifiers() {
+		records = append(records, toRecord(cl))
+	}
+	return &Checkpoint{
+		Version:              CheckpointVersion,
+		Params:               x.params,
+		Iteration:            state.iteration,
+		CumulativeMicroSteps: state.cumulativeMicroSteps,
+		MacroStep:            state.macroStep,
+		RandState:            x.rnd.State(),
+		Classifiers:          records,
+	}
+}
+
+// Resume continues the run captured by cp until the configured number
+// of iterations has been reached and returns the evolved population. The
+// parameters and random number generator state recorded in the
+// checkpoint replace those of x; the schedule of x is kept so that a run
+// can be extended.
+func (x *Xcs) Resume(problem mli.Problem, cp *Checkpoint) (*Population, error) {
+	if cp.Version < 1 || cp.Version > CheckpointVersion {
+		return nil, fmt.Errorf("unsupported checkpoint version %v", cp.Version)
+	}
+	if err := cp.Params.Validate(); err != nil {
+		return nil, err
+	}
+	if err := x.schedule.Validate(); err != nil {
+		return nil, err
+	}
+	state := &trainingState{
+		ruleSet:              NewPopulation(),
+		iteration:            cp.Iteration,
+		cumulativeMicroSteps: cp.CumulativeMicroSteps,
+		macroStep:            cp.MacroStep,
+	}
+	for _, r := range cp.Classifiers {
+		cl, err := fromRecord(cp.Params, r)
+		if err != nil {
+			return nil, err
+		}
+		state.ruleSet.Add(cl)
+	}
+	x.params = cp.Params
+	x.rnd.SetState(cp.RandState)
+	if err := x.train(problem, state); err != nil {
+		return nil, err
+	}
+	x.observer.TrainingFinished(TrainingEvent{state.iteration, state.ruleSet})
+	return state.ruleSet, nil
+}
+
+func WriteCheckpoint(w io.Writer, cp *Checkpoint) error {
+	return json.NewEncoder(w).Encode(cp)
+}
+
+func ReadCheckpoint(r io.Reader) (*Checkpoint, error) {
+	cp := &Checkpoint{Params: DefaultParams()}
+	if err := json.NewDecoder(r).Decode(cp); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// SaveCheckpointFile writes cp to a temporary file beside path and then
+// renames it, so that an interrupted write never replaces a good
+// checkpoint with a partial one.
+func SaveCheckpointFile(path string, cp *Checkpoint) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	if err := WriteCheckpoint(tmp, cp); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func LoadCheckpointFile(path string) (*Checkpoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cp, err := ReadCheckpoint(f)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return cp, nil
+}
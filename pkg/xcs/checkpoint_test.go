@@ -0,0 +1,80 @@
+package xcs_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/maze"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// TestResumeMatchesUninterruptedRun checks that a run resumed from a
+// checkpoint ends with the same population as the run that took it.
+func TestResumeMatchesUninterruptedRun(t *testing.T) {
+	problems := map[string]func(rnd *rng.Rand) (mli.Problem, error){
+		"multiplexer": func(rnd *rng.Rand) (mli.Problem, error) { return multiplexer.New(6, rnd) },
+		"woods1":      func(rnd *rng.Rand) (mli.Problem, error) { return maze.New("woods1", 50, rnd) },
+	}
+	for name, newProblem := range problems {
+		schedule := xcs.Schedule{Iterations: 1500, EvalInterval: 400, EvalSamples: 10, CheckpointInterval: 500}
+		params := xcs.DefaultParams()
+		params.MaxAction = 7
+		if name == "multiplexer" {
+			params.MaxAction = 1
+		}
+
+		run := func(seed int64) (*xcs.Xcs, mli.Problem) {
+			rnd := rng.New(seed)
+			problem, err := newProblem(rnd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x, err := xcs.New(params, rnd)
+			if err != nil {
+				t.Fatal(err)
+			}
+			x.SetObserver(xcs.NopObserver{})
+			if err := x.SetSchedule(schedule); err != nil {
+				t.Fatal(err)
+			}
+			return x, problem
+		}
+
+		x, problem := run(5)
+		var saved []byte
+		x.SetCheckpointHandler(func(cp *xcs.Checkpoint) error {
+			if cp.Iteration == 1000 {
+				var buf bytes.Buffer
+				if err := xcs.WriteCheckpoint(&buf, cp); err != nil {
+					return err
+				}
+				saved = buf.Bytes()
+			}
+			return nil
+		})
+		want, err := x.Train(problem)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if saved == nil {
+			t.Fatalf("%v: no checkpoint after iteration 1000", name)
+		}
+
+		cp, err := xcs.ReadCheckpoint(bytes.NewReader(saved))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The seed is replaced by the state in the checkpoint.
+		x, problem = run(99)
+		got, err := x.Resume(problem, cp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(encode(t, xcs.FormatJSON, params, got), encode(t, xcs.FormatJSON, params, want)) {
+			t.Errorf("%v: resumed run ended with a different population", name)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 type Classifier struct {
-	Condition       []string
+	Condition       Condition
 	Action          int
 	Payoff          float64
 	InitialError    float64
@@ -19,37 +19,35 @@ type Classifier struct {
 	ThetaSub        int64
 	TimeStamp       int64
 	V               float64
-	CorrectSets     []int
 	ThetaDel        int64
 	Delta           float64
 	ErrorZero       float64
+	Weights         []float64
+	RlsMatrix       []float64
+}
+
+func newClassifier(p Params, condition Condition, action int, step int64) *Classifier {
+	return &Classifier{condition, action, 0.0, p.InitialError, p.InitialError, p.FitnessI, p.FitnessI, 1, 0, 0, int64(p.ThetaSub), step, p.Nu, int64(p.ThetaDel), p.Delta, p.ErrorZero, nil, nil}
 }
 
 func (c *Classifier) ToString() string {
-	condition := c.Condition
-	builder := strings.Builder{}
-	for i := 0; i < len(condition); i++ {
-		builder.WriteString(condition[i])
-	}
-	condStr := builder.String()
-	return condStr + " --> " + strconv.Itoa(c.Action) +
+	return c.Condition.String() + " --> " + strconv.Itoa(c.Action) +
 		" [PAY:" + strconv.FormatFloat(c.Payoff, 'f', -1, 32) +
 		"; ERR: " + strconv.FormatFloat(c.PredictionError, 'f', -1, 32) + "]"
 }
 
-func (c *Classifier) UpdateCorrectSetSize(setSize int) float64 {
-	c.CorrectSets[len(c.CorrectSets)] = setSize
-	tot := 0
-	for i := 0; i < len(c.CorrectSets); i++ {
-		ss := c.CorrectSets[i]
-		tot += ss
+// UpdateCorrectSetSize moves the niche size estimate of a UCS classifier
+// towards setSize, the number of micro-classifiers in a correct set it
+// belongs to, and returns the new estimate. Like the action set size of
+// XCS, which it is stored as, it is a running mean while the classifier
+// has fewer than 1/beta updates and a moving average afterwards.
+func (c *Classifier) UpdateCorrectSetSize(setSize int32, beta float64) float64 {
+	if float64(c.Exp) < 1/beta {
+		c.ActionSetSize += (float64(setSize) - c.ActionSetSize) / float64(c.Exp)
+	} else {
+		c.ActionSetSize += beta * (float64(setSize) - c.ActionSetSize)
 	}
-	correctSetSize := float64(tot) / float64(len(c.CorrectSets))
-	return correctSetSize
-}
-
-func (c *Classifier) SetConditionComponent(n int, val string) {
-	c.Condition[n] = val
+	return c.ActionSetSize
 }
 
 func (c *Classifier) GetExperience() int64 {
@@ -60,7 +58,7 @@ func (c *Classifier) SetExperience(exp int64) {
 	c.Exp = exp
 }
 
-func (c *Classifier) GetCondition() []string {
+func (c *Classifier) GetCondition() Condition {
 	return c.Condition
 }
 
@@ -129,42 +127,53 @@ func (c *Classifier) DoesMatch(classifier *Classifier) bool {
 	if c.GetAction() != classifier.GetAction() {
 		return false
 	}
-	condition1 := c.GetCondition()
-	condition2 := classifier.GetCondition()
-	for i := 0; i < len(condition1); i++ {
-		if condition1[i] != condition2[i] {
-			return false
-		}
-	}
-	return true
+	return c.Condition.Equal(classifier.Condition)
 }
 
 func (c *Classifier) IsMoreGeneralThan(classifier *Classifier) bool {
-	if c.GetHashCount() <= classifier.GetHashCount() {
-		return false
-	}
-	condition1 := c.GetCondition()
-	condition2 := classifier.GetCondition()
-	for i := 0; i < len(condition1); i++ {
-		if condition1[i] != "#" && condition1[i] != condition2[i] {
-			return false
-		}
-	}
-	return true
+	return c.Condition.IsMoreGeneralThan(classifier.Condition)
 }
 
 func (c *Classifier) GetError() float64 {
 	return c.PredictionError
 }
 
+// GetGenerality returns the generality of the condition: the proportion
+// of '#' symbols of a ternary condition or the mean interval width of an
+// interval condition.
+func (c *Classifier) GetGenerality() float64 {
+	return c.Condition.Generality()
+}
+
+// GetHashCount returns the number of '#' symbols of a ternary or bitset
+// condition, and 0 for other conditions.
+//
+// Deprecated: Use GetGenerality, which applies to every condition type.
 func (c *Classifier) GetHashCount() int {
-	hashCount := 0
-	for _, x := range c.Condition {
-		if x == "#" {
-			hashCount += 1
+	switch c.Condition.(type) {
+	case TernaryCondition, BitsetCondition:
+		return strings.Count(c.Condition.String(), "#")
+	}
+	return 0
+}
+
+// SetConditionComponent sets position n of a ternary or bitset condition
+// to "0", "1" or "#", and leaves other conditions as they are. The
+// classifier must not be a member of a Population.
+//
+// Deprecated: Build the condition with ParseTernary or ParseBitset.
+func (c *Classifier) SetConditionComponent(n int, val string) {
+	switch condition := c.Condition.(type) {
+	case TernaryCondition:
+		condition[n] = val
+	case BitsetCondition:
+		switch val {
+		case "0", "1":
+			condition.set(n, val == "1")
+		case "#":
+			condition.clear(n)
 		}
 	}
-	return hashCount
 }
 
 func (c *Classifier) GetTimeStamp() int64 {
@@ -196,11 +205,7 @@ func (c *Classifier) SetTimeStamp(timeStamp int64) {
 }
 
 func (c *Classifier) GetOffspring() *Classifier {
-	condition := make([]string, len(c.Condition))
-	for i, a := range c.Condition {
-		condition[i] = a
-	}
-	cl := Classifier{condition, c.Action, 0.0, c.InitialError, c.InitialError, c.FitnessI, c.FitnessI, 1, 0, 0, c.ThetaSub, c.TimeStamp, c.V, make([]int, 80000), c.ThetaDel, c.Delta, c.ErrorZero}
+	cl := Classifier{c.Condition.Copy(), c.Action, 0.0, c.InitialError, c.InitialError, c.FitnessI, c.FitnessI, 1, 0, 0, c.ThetaSub, c.TimeStamp, c.V, c.ThetaDel, c.Delta, c.ErrorZero, copyFloats(c.Weights), copyFloats(c.RlsMatrix)}
 	cl.SetFitness(c.Fitness)
 	cl.SetPayoff(c.Payoff)
 	cl.SetPredictionError(c.PredictionError)
@@ -226,3 +231,22 @@ func (c *Classifier) SetError(predictionError float64) {
 func (c *Classifier) SetNumerosity(numerosity int32) {
 	c.Numerosity = numerosity
 }
+
+func (c *Classifier) copy() *Classifier {
+	cl := *c
+	cl.Condition = c.Condition.Copy()
+	cl.Weights = copyFloats(c.Weights)
+	cl.RlsMatrix = copyFloats(c.RlsMatrix)
+	return &cl
+}
+
+func copyFloats(values []float64) []float64 {
+	if values == nil {
+		return nil
+	}
+	return append([]float64(nil), values...)
+}
+
+func (c *Classifier) GetWeights() []float64 {
+	return c.Weights
+}
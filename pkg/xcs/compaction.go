@@ -0,0 +1,45 @@
+package xcs
+
+import "sort"
+
+// Compact returns a reduced copy of ruleSet. Classifiers with fewer than
+// minExperience updates or a prediction error of maxError or more are
+// dropped. The remainder are ordered by numerosity (then generality) and
+// any classifier that is subsumed by an earlier, more general
+// classifier advocating the same action is merged into it.
+func Compact(ruleSet *Population, minExperience int64, maxError float64) *Population {
+	candidates := make([]*Classifier, 0, ruleSet.Len())
+	for _, cl := range ruleSet.Classifiers() {
+		if cl.GetExperience() >= minExperience && cl.GetPredictionError() < maxError {
+			candidates = append(candidates, cl)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].GetNumerosity() != candidates[j].GetNumerosity() {
+			return candidates[i].GetNumerosity() > candidates[j].GetNumerosity()
+		}
+		return candidates[i].GetGenerality() > candidates[j].GetGenerality()
+	})
+
+	kept := make([]*Classifier, 0, len(candidates))
+	for _, cl := range candidates {
+		var subsumer *Classifier
+		for _, k := range kept {
+			if k.GetAction() == cl.GetAction() && k.IsMoreGeneralThan(cl) {
+				subsumer = k
+				break
+			}
+		}
+		if subsumer != nil {
+			subsumer.IncrementNumerosityBy(cl.GetNumerosity())
+			continue
+		}
+		kept = append(kept, cl.copy())
+	}
+
+	compacted := NewPopulation()
+	for _, cl := range kept {
+		compacted.Add(cl)
+	}
+	return compacted
+}
@@ -0,0 +1,257 @@
+package xcs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+)
+
+// Condition is the part of a classifier that decides which inputs it
+// matches. Xcs uses a Condition only through this interface, so new
+// encodings can be plugged in with Xcs.SetConditionType.
+//
+// Cover is called on a prototype (usually the zero value of the type)
+// and returns a new condition matching the data item. Mutate and
+// Crossover change the receiver in place; Crossover exchanges the
+// positions chosen by CrossoverMask with a condition of the same type
+// and length, and leaves both conditions as they are otherwise.
+// IsMoreGeneralThan reports whether the receiver matches every input the
+// other condition matches and is not equal to it. Generality is in
+// [0, 1] for conditions over inputs in [0, 1].
+type Condition interface {
+	Matches(dataItem mli.DataItem) bool
+	Cover(dataItem mli.DataItem, p *Params, rnd rng.Generator) (Condition, error)
+	Mutate(dataItem mli.DataItem, p *Params, rnd rng.Generator)
+	Crossover(other Condition, p *Params, rnd rng.Generator)
+	IsMoreGeneralThan(other Condition) bool
+	Generality() float64
+	Equal(other Condition) bool
+	String() string
+	// Len returns the number of inputs the condition applies to.
+	Len() int
+	Copy() Condition
+}
+
+// TernaryCondition is the condition of XCS: one of "0", "1" or "#" (don't
+// care) for each binary input.
+type TernaryCondition []string
+
+// ParseTernary reads a condition written as a string of 0, 1 and #
+// symbols.
+func ParseTernary(s string) (TernaryCondition, error) {
+	condition := TernaryCondition(strings.Split(s, ""))
+	for i, symbol := range condition {
+		if symbol != "0" && symbol != "1" && symbol != "#" {
+			return nil, fmt.Errorf("invalid condition %q: unexpected symbol %q at position %v", s, symbol, i)
+		}
+	}
+	return condition, nil
+}
+
+func (t TernaryCondition) Matches(dataItem mli.DataItem) bool {
+	inputs := dataItem.GetInputs()
+	for idx := 0; idx < len(inputs); idx++ {
+		if t[idx] == "0" {
+			if inputs[idx] != 0 {
+				return false
+			}
+		} else if t[idx] == "1" {
+			if inputs[idx] != 1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Cover copies the inputs, replacing each by '#' with probability PHash.
+// It fails with ErrInvalidInput for inputs other than 0 and 1.
+func (t TernaryCondition) Cover(dataItem mli.DataItem, p *Params, rnd rng.Generator) (Condition, error) {
+	condition := make(TernaryCondition, len(dataItem.GetInputs()))
+	for i, attrib := range dataItem.GetInputs() {
+		if attrib != 0 && attrib != 1 {
+			return nil, fmt.Errorf("%w: attribute %v of %v is %v, expected 0 or 1", ErrInvalidInput, i, dataItem.ToString(), attrib)
+		}
+		if rnd.Float64() < p.PHash {
+			condition[i] = "#"
+		} else {
+			condition[i] = strconv.Itoa(attrib)
+		}
+	}
+	return condition, nil
+}
+
+// Mutate changes each symbol with probability Mu as set out by
+// mutateSymbol.
+func (t TernaryCondition) Mutate(dataItem mli.DataItem, p *Params, rnd rng.Generator) {
+	for k := 0; k < len(t); k++ {
+		if rnd.Float64() < p.Mu {
+			value, _ := strconv.Atoi(t[k])
+			specified, value := mutateSymbol(t[k] != "#", value, dataItem.GetAttribute(k), p, rnd)
+			if specified {
+				t[k] = strconv.Itoa(value)
+			} else {
+				t[k] = "#"
+			}
+		}
+	}
+}
+
+// mutateSymbol returns the mutation of a ternary symbol, given as whether
+// it is specified and its value, for the binary input. Under free
+// mutation a specified symbol becomes '#' with probability PGeneralize
+// and otherwise flips to the other value; under niche mutation it
+// becomes '#' with probability 2*PGeneralize and otherwise stays. A '#'
+// is specified with probability 2*(1-PGeneralize), as a random value
+// under free mutation and as the input under niche mutation.
+// Probabilities above 1 count as 1.
+func mutateSymbol(specified bool, value, input int, p *Params, rnd rng.Generator) (bool, int) {
+	if specified {
+		if p.Mutation == MutationFree {
+			if rnd.Float64() < p.PGeneralize {
+				return false, 0
+			}
+			return true, 1 - value
+		}
+		if chance(2*p.PGeneralize, rnd) {
+			return false, 0
+		}
+		return true, value
+	}
+	if !chance(2*(1-p.PGeneralize), rnd) {
+		return false, 0
+	}
+	if p.Mutation == MutationFree {
+		return true, rnd.Intn(2)
+	}
+	return true, input
+}
+
+// chance reports whether an event of probability q happens, drawing a
+// random number only if q is below 1.
+func chance(q float64, rnd rng.Generator) bool {
+	return q >= 1 || rnd.Float64() < q
+}
+
+// Crossover swaps the symbols chosen by CrossoverMask.
+func (t TernaryCondition) Crossover(other Condition, p *Params, rnd rng.Generator) {
+	o, ok := other.(TernaryCondition)
+	if !ok || len(o) != len(t) {
+		return
+	}
+	for m, swap := range CrossoverMask(len(t), p, rnd) {
+		if swap {
+			t[m], o[m] = o[m], t[m]
+		}
+	}
+}
+
+// CrossoverMask returns which of n positions two offspring exchange under
+// the crossover operator named by Params.Crossover. Each cut point
+// toggles the exchange from that position onwards. One-point and n-point
+// crossover cut at distinct points in [1, n), at most n-1 of them, so
+// that every cut changes the mask. Two-point crossover draws its points
+// from [0, n) independently, as Butz and Wilson (2000) do, so the
+// exchanged segment may be empty.
+func CrossoverMask(n int, p *Params, rnd rng.Generator) []bool {
+	mask := make([]bool, n)
+	if n == 0 {
+		return mask
+	}
+	var points []int
+	switch p.Crossover {
+	case CrossoverUniform:
+		for i := range mask {
+			mask[i] = rnd.Float64() < 0.5
+		}
+		return mask
+	case CrossoverOnePoint:
+		points = cutPoints(n, 1, rnd)
+	case CrossoverNPoint:
+		points = cutPoints(n, p.CrossoverPoints, rnd)
+	default:
+		points = []int{rnd.Intn(n), rnd.Intn(n)}
+	}
+	for _, point := range points {
+		for i := point; i < n; i++ {
+			mask[i] = !mask[i]
+		}
+	}
+	return mask
+}
+
+// cutPoints returns k distinct points drawn from [1, n), or all of them
+// if there are fewer than k.
+func cutPoints(n, k int, rnd rng.Generator) []int {
+	candidates := make([]int, n-1)
+	for i := range candidates {
+		candidates[i] = i + 1
+	}
+	if k > len(candidates) {
+		k = len(candidates)
+	}
+	for i := 0; i < k; i++ {
+		j := i + rnd.Intn(len(candidates)-i)
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+	return candidates[:k]
+}
+
+func (t TernaryCondition) IsMoreGeneralThan(other Condition) bool {
+	o, ok := other.(TernaryCondition)
+	if !ok || t.hashCount() <= o.hashCount() {
+		return false
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] != "#" && t[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Generality returns the proportion of '#' symbols.
+func (t TernaryCondition) Generality() float64 {
+	if len(t) == 0 {
+		return 0
+	}
+	return float64(t.hashCount()) / float64(len(t))
+}
+
+func (t TernaryCondition) Equal(other Condition) bool {
+	o, ok := other.(TernaryCondition)
+	if !ok || len(t) != len(o) {
+		return false
+	}
+	for i := 0; i < len(t); i++ {
+		if t[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (t TernaryCondition) String() string {
+	return strings.Join(t, "")
+}
+
+func (t TernaryCondition) Len() int {
+	return len(t)
+}
+
+func (t TernaryCondition) Copy() Condition {
+	return append(TernaryCondition(nil), t...)
+}
+
+func (t TernaryCondition) hashCount() int {
+	hashCount := 0
+	for _, x := range t {
+		if x == "#" {
+			hashCount += 1
+		}
+	}
+	return hashCount
+}
@@ -0,0 +1,135 @@
+package xcs_test
+
+import (
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// transitions counts the positions at which mask differs from the
+// position before, taking the position before the first as false.
+func transitions(mask []bool) int {
+	count := 0
+	previous := false
+	for _, exchange := range mask {
+		if exchange != previous {
+			count++
+		}
+		previous = exchange
+	}
+	return count
+}
+
+func crossoverParams(crossover string, points int) *xcs.Params {
+	params := xcs.DefaultParams()
+	params.Crossover = crossover
+	params.CrossoverPoints = points
+	return &params
+}
+
+func TestCrossoverMaskOnePoint(t *testing.T) {
+	rnd := rng.New(1)
+	params := crossoverParams(xcs.CrossoverOnePoint, 0)
+	for i := 0; i < 1000; i++ {
+		n := 2 + i%10
+		mask := xcs.CrossoverMask(n, params, rnd)
+		if mask[0] || !mask[n-1] || transitions(mask) != 1 {
+			t.Fatalf("one-point mask %v is not a proper suffix", mask)
+		}
+	}
+}
+
+func TestCrossoverMaskTwoPoint(t *testing.T) {
+	rnd := rng.New(1)
+	params := crossoverParams(xcs.CrossoverTwoPoint, 0)
+	for i := 0; i < 1000; i++ {
+		mask := xcs.CrossoverMask(1+i%10, params, rnd)
+		if count := transitions(mask); count > 2 {
+			t.Fatalf("two-point mask %v has %v transitions", mask, count)
+		}
+	}
+}
+
+func TestCrossoverMaskNPoint(t *testing.T) {
+	rnd := rng.New(1)
+	for points := 1; points <= 12; points++ {
+		params := crossoverParams(xcs.CrossoverNPoint, points)
+		for n := 1; n <= 10; n++ {
+			want := points
+			if want > n-1 {
+				want = n - 1
+			}
+			for i := 0; i < 50; i++ {
+				mask := xcs.CrossoverMask(n, params, rnd)
+				if mask[0] || transitions(mask) != want {
+					t.Fatalf("%v-point mask of length %v is %v, want %v cuts", points, n, mask, want)
+				}
+			}
+		}
+	}
+}
+
+func TestCrossoverMaskUniform(t *testing.T) {
+	rnd := rng.New(1)
+	params := crossoverParams(xcs.CrossoverUniform, 0)
+	exchanged := 0
+	for i := 0; i < 1000; i++ {
+		for _, exchange := range xcs.CrossoverMask(10, params, rnd) {
+			if exchange {
+				exchanged++
+			}
+		}
+	}
+	if exchanged < 4500 || exchanged > 5500 {
+		t.Errorf("uniform crossover exchanged %v of 10000 positions", exchanged)
+	}
+}
+
+func TestCrossoverMaskShortConditions(t *testing.T) {
+	for _, crossover := range []string{xcs.CrossoverUniform, xcs.CrossoverOnePoint, xcs.CrossoverTwoPoint, xcs.CrossoverNPoint} {
+		params := crossoverParams(crossover, 3)
+		if mask := xcs.CrossoverMask(0, params, rng.New(1)); len(mask) != 0 {
+			t.Errorf("%v: mask of length 0 is %v", crossover, mask)
+		}
+		mask := xcs.CrossoverMask(1, params, rng.New(1))
+		if len(mask) != 1 {
+			t.Errorf("%v: mask of length 1 is %v", crossover, mask)
+		}
+		if crossover != xcs.CrossoverUniform && crossover != xcs.CrossoverTwoPoint && mask[0] {
+			t.Errorf("%v: exchanged the only position", crossover)
+		}
+	}
+}
+
+func TestCrossoverIgnoresMismatchedConditions(t *testing.T) {
+	params := xcs.DefaultParams()
+	params.Crossover = xcs.CrossoverUniform
+	ternary, bitset := conditionPair(t, "0000")
+	longer, _ := conditionPair(t, "111111")
+	interval := xcs.IntervalCondition{{Lower: 0, Upper: 1}, {Lower: 0, Upper: 1}}
+	for _, pair := range [][2]xcs.Condition{{ternary, bitset}, {bitset, ternary}, {ternary, longer}, {longer, ternary}, {interval, ternary}} {
+		before := [2]string{pair[0].String(), pair[1].String()}
+		pair[0].Crossover(pair[1], &params, rng.New(1))
+		if after := [2]string{pair[0].String(), pair[1].String()}; after != before {
+			t.Errorf("crossover of %v and %v gave %v and %v", before[0], before[1], after[0], after[1])
+		}
+	}
+}
+
+func TestIntervalCrossoverStaysInRange(t *testing.T) {
+	params := xcs.DefaultParams()
+	params.Condition = xcs.ConditionReal
+	params.Crossover = xcs.CrossoverUniform
+	rnd := rng.New(1)
+	for i := 0; i < 100; i++ {
+		c1 := xcs.IntervalCondition{{Lower: 0, Upper: 0.8}}
+		c2 := xcs.IntervalCondition{{Lower: 0.9, Upper: 1}}
+		c1.Crossover(c2, &params, rnd)
+		for _, iv := range append(c1, c2...) {
+			if iv.Lower < params.InputMin || iv.Upper > params.InputMax {
+				t.Fatalf("crossover gave %v, outside [%v, %v]", iv, params.InputMin, params.InputMax)
+			}
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package xcs
+
+import "errors"
+
+var (
+	// ErrEmptyActionSet is returned when an operation needs at least one
+	// classifier in an action set but was given none.
+	ErrEmptyActionSet = errors.New("action set is empty")
+	// ErrConditionLength is returned when the length of a classifier's
+	// condition differs from the number of inputs of a data item.
+	ErrConditionLength = errors.New("condition length does not match the number of inputs")
+	// ErrInvalidInput is returned when an input value cannot be
+	// represented by the condition of a classifier.
+	ErrInvalidInput = errors.New("invalid input value")
+	// ErrInvalidParam is wrapped by the errors InvalidParam returns for
+	// parameters and schedule settings outside their permitted range.
+	ErrInvalidParam = errors.New("invalid parameter")
+	// ErrNoAction is returned when no action can be chosen for a new
+	// classifier.
+	ErrNoAction = errors.New("no action available")
+	// ErrWrongAlgorithm is returned by Load for a population evolved by
+	// an algorithm other than XCS, such as UCS or ZCS.
+	ErrWrongAlgorithm = errors.New("population was evolved by another algorithm")
+	// ErrNoMatch is returned by Model when no classifier matches the
+	// inputs.
+	ErrNoMatch = errors.New("no classifier matches the inputs")
+)
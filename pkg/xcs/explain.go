@@ -0,0 +1,126 @@
+package xcs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+)
+
+// Explanation describes how a Model arrived at the action it chose for
+// a set of inputs: the match set grouped by action, with the share each
+// classifier has in its action's system prediction.
+type Explanation struct {
+	Inputs     []int
+	RealInputs []float64
+	Matched    bool
+	Action     int
+	Groups     []ActionGroup
+}
+
+// ActionGroup holds the matching classifiers advocating one action. The
+// system prediction is the fitness-weighted average of their payoffs, as
+// computed by CreatePredictionArray.
+type ActionGroup struct {
+	Action     int
+	Prediction float64
+	FitnessSum float64
+	Rules      []RuleContribution
+}
+
+// RuleContribution describes one matching classifier. Share is its
+// fitness divided by the fitness sum of its action group, and
+// Contribution is Share times its payoff; the contributions of a group
+// add up to the group's prediction. With computed prediction, Payoff is
+// the classifier's prediction for the inputs.
+type RuleContribution struct {
+	Condition    string
+	Payoff       float64
+	Error        float64
+	Fitness      float64
+	Numerosity   int32
+	Experience   int64
+	Share        float64
+	Contribution float64
+}
+
+// Explain returns the explanation of the prediction for the inputs.
+// Groups are ordered by descending prediction and rules by descending
+// share. An input matched by no classifier gives an explanation with
+// Matched set to false rather than an error.
+func (m *Model) Explain(inputs []int) (Explanation, error) {
+	return m.explain(Explanation{Inputs: inputs}, inputItem(inputs))
+}
+
+// ExplainReal is Explain for real-valued inputs.
+func (m *Model) ExplainReal(inputs []float64) (Explanation, error) {
+	return m.explain(Explanation{RealInputs: inputs}, realInputItem(inputs))
+}
+
+func (m *Model) explain(ex Explanation, item mli.DataItem) (Explanation, error) {
+	matchSet, err := m.x.ObtainMatchingClassifiers(m.ruleSet, item)
+	if err != nil {
+		return Explanation{}, err
+	}
+	if len(matchSet) == 0 {
+		return ex, nil
+	}
+	predictionArray := m.x.CreatePredictionArray(matchSet, item)
+	inputs := m.x.predictorInputs(item)
+	ex.Matched = true
+	ex.Action = bestAction(predictionArray)
+
+	groups := make(map[int]*ActionGroup)
+	for _, cl := range matchSet {
+		g, ok := groups[cl.GetAction()]
+		if !ok {
+			g = &ActionGroup{Action: cl.GetAction(), Prediction: predictionArray[cl.GetAction()]}
+			groups[cl.GetAction()] = g
+		}
+		g.FitnessSum += cl.GetFitness()
+		g.Rules = append(g.Rules, RuleContribution{
+			Condition:  cl.GetCondition().String(),
+			Payoff:     m.x.prediction(cl, inputs),
+			Error:      cl.GetPredictionError(),
+			Fitness:    cl.GetFitness(),
+			Numerosity: cl.GetNumerosity(),
+			Experience: cl.GetExperience(),
+		})
+	}
+	for _, a := range sortedActions(predictionArray) {
+		g := groups[a]
+		for i := range g.Rules {
+			r := &g.Rules[i]
+			if g.FitnessSum > 0 {
+				r.Share = r.Fitness / g.FitnessSum
+			}
+			r.Contribution = r.Share * r.Payoff
+		}
+		sort.SliceStable(g.Rules, func(i, j int) bool { return g.Rules[i].Share > g.Rules[j].Share })
+		ex.Groups = append(ex.Groups, *g)
+	}
+	sort.SliceStable(ex.Groups, func(i, j int) bool { return ex.Groups[i].Prediction > ex.Groups[j].Prediction })
+	return ex, nil
+}
+
+func (e Explanation) String() string {
+	builder := strings.Builder{}
+	input := inputItem(e.Inputs).ToString()
+	if e.RealInputs != nil {
+		input = realInputItem(e.RealInputs).ToString()
+	}
+	if !e.Matched {
+		fmt.Fprintf(&builder, "Input %v: no classifier matches\n", input)
+		return builder.String()
+	}
+	fmt.Fprintf(&builder, "Input %v: action %v chosen\n", input, e.Action)
+	for _, g := range e.Groups {
+		fmt.Fprintf(&builder, "  Action %v: prediction %.2f from %v rule(s)\n", g.Action, g.Prediction, len(g.Rules))
+		for _, r := range g.Rules {
+			fmt.Fprintf(&builder, "    %v  payoff %.2f  error %.2f  fitness %.4f  numerosity %v  share %.1f%%\n",
+				r.Condition, r.Payoff, r.Error, r.Fitness, r.Numerosity, r.Share*100)
+		}
+	}
+	return builder.String()
+}
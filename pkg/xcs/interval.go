@@ -0,0 +1,231 @@
+package xcs
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+)
+
+// Interval is one attribute of a real-valued (XCSR) or integer-valued
+// (XCSI) condition. It matches inputs in [Lower, Upper]. The bounds are
+// stored in this form for both interval encodings; the encoding only
+// changes how covering, mutation and crossover treat them. The bounds of
+// integer intervals are whole numbers.
+type Interval struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+}
+
+func (i Interval) Contains(v float64) bool {
+	return i.Lower <= v && v <= i.Upper
+}
+
+func (i Interval) center() float64 {
+	return (i.Lower + i.Upper) / 2
+}
+
+func (i Interval) spread() float64 {
+	return (i.Upper - i.Lower) / 2
+}
+
+// IntervalCondition is a condition of one interval per input. Whether it
+// is real- or integer-valued, and how its alleles are encoded, is taken
+// from the Params passed to Cover, Mutate and Crossover.
+type IntervalCondition []Interval
+
+// realInputs returns the inputs of a data item as real values, using
+// GetRealInputs where the data item provides it.
+func realInputs(dataItem mli.DataItem) []float64 {
+	if r, ok := dataItem.(mli.RealDataItem); ok {
+		return r.GetRealInputs()
+	}
+	ints := dataItem.GetInputs()
+	inputs := make([]float64, len(ints))
+	for i, v := range ints {
+		inputs[i] = float64(v)
+	}
+	return inputs
+}
+
+func (c IntervalCondition) Matches(dataItem mli.DataItem) bool {
+	for i, v := range realInputs(dataItem) {
+		if !c[i].Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Cover creates intervals around each input with a random spread of at
+// most SpreadLimit.
+func (c IntervalCondition) Cover(dataItem mli.DataItem, p *Params, rnd rng.Generator) (Condition, error) {
+	inputs := realInputs(dataItem)
+	intervals := make(IntervalCondition, len(inputs))
+	for i, v := range inputs {
+		if p.Condition == ConditionInteger {
+			r := int(p.SpreadLimit) + 1
+			intervals[i] = Interval{v - float64(rnd.Intn(r)), v + float64(rnd.Intn(r))}
+		} else if p.IntervalEncoding == EncodingLowerUpper {
+			intervals[i] = Interval{v - rnd.Float64()*p.SpreadLimit, v + rnd.Float64()*p.SpreadLimit}
+		} else {
+			s := rnd.Float64() * p.SpreadLimit
+			intervals[i] = Interval{v - s, v + s}
+		}
+		intervals[i] = clampInterval(intervals[i], p)
+	}
+	return intervals, nil
+}
+
+// Mutate moves each allele (the center and spread, or the two bounds)
+// with probability Mu by a random amount of at most MutationSpread.
+func (c IntervalCondition) Mutate(dataItem mli.DataItem, p *Params, rnd rng.Generator) {
+	m := p.MutationSpread
+	shift := func() float64 { return (rnd.Float64()*2 - 1) * m }
+	if p.Condition == ConditionInteger {
+		shift = func() float64 { return float64(rnd.Intn(2*int(m)+1) - int(m)) }
+	}
+	for i, iv := range c {
+		if lowerUpper(p) {
+			if rnd.Float64() < p.Mu {
+				iv.Lower += shift()
+			}
+			if rnd.Float64() < p.Mu {
+				iv.Upper += shift()
+			}
+			if iv.Lower > iv.Upper {
+				iv.Lower, iv.Upper = iv.Upper, iv.Lower
+			}
+		} else {
+			center, spread := iv.center(), iv.spread()
+			if rnd.Float64() < p.Mu {
+				center += shift()
+			}
+			if rnd.Float64() < p.Mu {
+				spread = math.Abs(spread + shift())
+			}
+			iv = Interval{center - spread, center + spread}
+		}
+		c[i] = clampInterval(iv, p)
+	}
+}
+
+// Crossover exchanges the alleles chosen by CrossoverMask between two
+// conditions, each attribute contributing two alleles (center and
+// spread, or lower and upper bound).
+func (c IntervalCondition) Crossover(other Condition, p *Params, rnd rng.Generator) {
+	o, ok := other.(IntervalCondition)
+	if !ok || len(o) != len(c) {
+		return
+	}
+	alleles1 := c.alleles(p)
+	alleles2 := o.alleles(p)
+	for k, swap := range CrossoverMask(len(alleles1), p, rnd) {
+		if swap {
+			alleles1[k], alleles2[k] = alleles2[k], alleles1[k]
+		}
+	}
+	c.setAlleles(alleles1, p)
+	o.setAlleles(alleles2, p)
+}
+
+func (c IntervalCondition) alleles(p *Params) []float64 {
+	alleles := make([]float64, 0, 2*len(c))
+	for _, iv := range c {
+		if lowerUpper(p) {
+			alleles = append(alleles, iv.Lower, iv.Upper)
+		} else {
+			alleles = append(alleles, iv.center(), iv.spread())
+		}
+	}
+	return alleles
+}
+
+// setAlleles sets the intervals from their alleles, clipped to
+// [InputMin, InputMax] as covering and mutation clip them.
+func (c IntervalCondition) setAlleles(alleles []float64, p *Params) {
+	for i := range c {
+		a, b := alleles[2*i], alleles[2*i+1]
+		if lowerUpper(p) {
+			c[i] = clampInterval(Interval{math.Min(a, b), math.Max(a, b)}, p)
+		} else {
+			c[i] = clampInterval(Interval{a - b, a + b}, p)
+		}
+	}
+}
+
+// lowerUpper reports whether the alleles of an interval are its bounds
+// rather than its center and spread.
+func lowerUpper(p *Params) bool {
+	return p.Condition == ConditionInteger || p.IntervalEncoding == EncodingLowerUpper
+}
+
+func clampInterval(iv Interval, p *Params) Interval {
+	return Interval{math.Max(iv.Lower, p.InputMin), math.Min(iv.Upper, p.InputMax)}
+}
+
+// IsMoreGeneralThan reports whether every interval contains the
+// corresponding interval of other and the two are not identical.
+func (c IntervalCondition) IsMoreGeneralThan(other Condition) bool {
+	o, ok := other.(IntervalCondition)
+	if !ok || len(c) != len(o) {
+		return false
+	}
+	strictly := false
+	for i := range c {
+		if c[i].Lower > o[i].Lower || c[i].Upper < o[i].Upper {
+			return false
+		}
+		if c[i] != o[i] {
+			strictly = true
+		}
+	}
+	return strictly
+}
+
+// Generality returns the mean width of the intervals.
+func (c IntervalCondition) Generality() float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, iv := range c {
+		sum += iv.Upper - iv.Lower
+	}
+	return sum / float64(len(c))
+}
+
+func (c IntervalCondition) Equal(other Condition) bool {
+	o, ok := other.(IntervalCondition)
+	if !ok || len(c) != len(o) {
+		return false
+	}
+	for i := range c {
+		if c[i] != o[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (c IntervalCondition) String() string {
+	parts := make([]string, len(c))
+	f := func(v float64) string {
+		s := strconv.FormatFloat(v, 'f', 3, 64)
+		return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	}
+	for i, iv := range c {
+		parts[i] = "[" + f(iv.Lower) + "," + f(iv.Upper) + "]"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c IntervalCondition) Len() int {
+	return len(c)
+}
+
+func (c IntervalCondition) Copy() Condition {
+	return append(IntervalCondition(nil), c...)
+}
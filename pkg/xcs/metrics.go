@@ -0,0 +1,118 @@
+package xcs
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type MetricsFormat int
+
+const (
+	MetricsCSV MetricsFormat = iota
+	MetricsJSONLines
+)
+
+// MetricsFormatForPath returns MetricsJSONLines for paths ending in
+// .jsonl or .ndjson and MetricsCSV otherwise.
+func MetricsFormatForPath(path string) MetricsFormat {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl", ".ndjson":
+		return MetricsJSONLines
+	}
+	return MetricsCSV
+}
+
+// MetricsRecord is one point of a learning curve.
+type MetricsRecord struct {
+	Iteration         int     `json:"iteration"`
+	Accuracy          float64 `json:"accuracy"`
+	AverageSteps      float64 `json:"averageSteps"`
+	AverageReward     float64 `json:"averageReward"`
+	SystemError       float64 `json:"systemError"`
+	MacroClassifiers  int     `json:"macroClassifiers"`
+	MicroClassifiers  int32   `json:"microClassifiers"`
+	AverageGenerality float64 `json:"averageGenerality"`
+	AverageFitness    float64 `json:"averageFitness"`
+}
+
+var metricsHeader = []string{"iteration", "accuracy", "averageSteps", "averageReward", "systemError", "macroClassifiers", "microClassifiers", "averageGenerality", "averageFitness"}
+
+func (r MetricsRecord) fields() []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'g', -1, 64) }
+	return []string{
+		strconv.Itoa(r.Iteration), f(r.Accuracy), f(r.AverageSteps), f(r.AverageReward), f(r.SystemError),
+		strconv.Itoa(r.MacroClassifiers), strconv.Itoa(int(r.MicroClassifiers)),
+		f(r.AverageGenerality), f(r.AverageFitness),
+	}
+}
+
+// MetricsWriter is an Observer that writes a MetricsRecord for every
+// evaluation, as CSV (with a header row) or as JSON Lines. Observer
+// methods cannot return errors, so the first write error is kept and
+// reported by Flush; nothing more is written after it.
+type MetricsWriter struct {
+	NopObserver
+	format MetricsFormat
+	csv    *csv.Writer
+	json   *json.Encoder
+	header bool
+	err    error
+}
+
+func NewMetricsWriter(w io.Writer, format MetricsFormat) *MetricsWriter {
+	m := &MetricsWriter{format: format}
+	if format == MetricsJSONLines {
+		m.json = json.NewEncoder(w)
+	} else {
+		m.csv = csv.NewWriter(w)
+	}
+	return m
+}
+
+func (m *MetricsWriter) EvaluationFinished(e EvaluationEvent) {
+	m.Write(MetricsRecord{
+		Iteration:         e.MacroStep,
+		Accuracy:          e.ProportionCorrect,
+		AverageSteps:      e.AverageSteps,
+		AverageReward:     e.AverageReward,
+		SystemError:       e.SystemError,
+		MacroClassifiers:  e.MacroClassifiers,
+		MicroClassifiers:  e.MicroClassifiers,
+		AverageGenerality: e.AverageGenerality,
+		AverageFitness:    e.AverageFitness,
+	})
+}
+
+func (m *MetricsWriter) Write(r MetricsRecord) {
+	if m.err != nil {
+		return
+	}
+	if m.format == MetricsJSONLines {
+		m.err = m.json.Encode(r)
+		return
+	}
+	if !m.header {
+		m.header = true
+		if m.err = m.csv.Write(metricsHeader); m.err != nil {
+			return
+		}
+	}
+	m.err = m.csv.Write(r.fields())
+}
+
+// Flush writes any buffered data and returns the first error met.
+func (m *MetricsWriter) Flush() error {
+	if m.csv != nil && m.err == nil {
+		m.csv.Flush()
+		m.err = m.csv.Error()
+	}
+	if m.err != nil {
+		return fmt.Errorf("writing metrics: %w", m.err)
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package xcs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+)
+
+// Model answers queries with a trained population. It never changes the
+// population, so a Model may be used by several goroutines at once.
+type Model struct {
+	algorithm string
+	params    Params
+	ruleSet   *Population
+	x         *Xcs
+}
+
+// NewModel returns a Model of a population evolved by XCS.
+func NewModel(params Params, ruleSet *Population) (*Model, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return &Model{Algorithm, params, ruleSet, &Xcs{params: params, observer: NopObserver{}}}, nil
+}
+
+// LoadModel reads a population saved with Save, SaveFile or their
+// WithAlgorithm forms. GetAlgorithm names the algorithm that evolved it.
+func LoadModel(path string) (*Model, error) {
+	algorithm, params, ruleSet, err := LoadFileWithAlgorithm(path)
+	if err != nil {
+		return nil, err
+	}
+	m, err := NewModel(params, ruleSet)
+	if err != nil {
+		return nil, err
+	}
+	m.algorithm = algorithm
+	return m, nil
+}
+
+// GetAlgorithm returns the name of the algorithm that evolved the
+// population, such as Algorithm for XCS.
+func (m *Model) GetAlgorithm() string {
+	return m.algorithm
+}
+
+// SetPredictionFunc replaces the computation of prediction arrays, as
+// Xcs.SetPredictionFunc does; populations evolved by UCS are queried
+// with ucs.Vote. Call it before the Model is shared.
+func (m *Model) SetPredictionFunc(f PredictionFunc) {
+	m.x.SetPredictionFunc(f)
+}
+
+func (m *Model) GetParams() Params {
+	return m.params
+}
+
+func (m *Model) GetPopulation() *Population {
+	return m.ruleSet
+}
+
+// Predict returns the action with the highest system prediction for the
+// inputs, or ErrNoMatch if no classifier matches them.
+func (m *Model) Predict(inputs []int) (int, error) {
+	action, _, err := m.x.exploit(m.ruleSet, inputItem(inputs))
+	return action, err
+}
+
+// PredictReal is Predict for real-valued inputs, as used by populations
+// with interval conditions.
+func (m *Model) PredictReal(inputs []float64) (int, error) {
+	action, _, err := m.x.exploit(m.ruleSet, realInputItem(inputs))
+	return action, err
+}
+
+// PredictionArray returns the system prediction of every action
+// advocated by a classifier matching the inputs, or ErrNoMatch if no
+// classifier matches them.
+func (m *Model) PredictionArray(inputs []int) (map[int]float64, error) {
+	return m.predictionArray(inputItem(inputs))
+}
+
+func (m *Model) PredictionArrayReal(inputs []float64) (map[int]float64, error) {
+	return m.predictionArray(realInputItem(inputs))
+}
+
+func (m *Model) predictionArray(item mli.DataItem) (map[int]float64, error) {
+	matchSet, err := m.x.ObtainMatchingClassifiers(m.ruleSet, item)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchSet) == 0 {
+		return nil, ErrNoMatch
+	}
+	return m.x.CreatePredictionArray(matchSet, item), nil
+}
+
+// inputItem adapts raw inputs to mli.DataItem for queries, where the
+// answer is unknown.
+type inputItem []int
+
+func (i inputItem) GetInputs() []int {
+	return i
+}
+
+func (i inputItem) GetAnswer() int {
+	return -1
+}
+
+func (i inputItem) GetAttribute(n int) int {
+	return i[n]
+}
+
+func (i inputItem) ToString() string {
+	builder := strings.Builder{}
+	for _, v := range i {
+		builder.WriteString(strconv.Itoa(v))
+	}
+	return builder.String()
+}
+
+// realInputItem adapts real-valued inputs to mli.RealDataItem. Its
+// integer inputs are the real ones truncated.
+type realInputItem []float64
+
+func (i realInputItem) GetInputs() []int {
+	inputs := make([]int, len(i))
+	for n, v := range i {
+		inputs[n] = int(v)
+	}
+	return inputs
+}
+
+func (i realInputItem) GetRealInputs() []float64 {
+	return i
+}
+
+func (i realInputItem) GetAnswer() int {
+	return -1
+}
+
+func (i realInputItem) GetAttribute(n int) int {
+	return int(i[n])
+}
+
+func (i realInputItem) ToString() string {
+	parts := make([]string, len(i))
+	for n, v := range i {
+		parts[n] = strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
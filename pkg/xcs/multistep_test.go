@@ -0,0 +1,91 @@
+package xcs_test
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/maze"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// chain is a two-step problem: either action leads from state 0 to
+// state 1 without reward, and in state 1 action 0 earns 1000 and action
+// 1 nothing before the episode ends. If cutOff is set, every episode
+// reports that it was cut off.
+type chain struct {
+	state  int
+	end    bool
+	cutOff bool
+}
+
+func (c *chain) IsAtEndState() bool        { return c.end }
+func (c *chain) Reset()                    { c.state, c.end = 0, false }
+func (c *chain) ObtainInput() mli.DataItem { return &maze.Percept{Inputs: []int{c.state}} }
+func (c *chain) Truncated() bool           { return c.end && c.cutOff }
+
+func (c *chain) Effect(action int) int {
+	if c.state == 0 {
+		c.state = 1
+		return 0
+	}
+	c.end = true
+	if action == 0 {
+		return 1000
+	}
+	return 0
+}
+
+func trainChain(t *testing.T, problem *chain) *xcs.Population {
+	t.Helper()
+	params := xcs.DefaultParams()
+	params.MaxAction = 1
+	params.PHash = 0
+	params.PExplore = 1
+	params.ThetaGa = 1000000
+	params.DoGaSubsumption = false
+	params.ActionSetSubsumption = false
+	x, err := xcs.New(params, rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.SetObserver(xcs.NopObserver{})
+	if err := x.SetSchedule(xcs.Schedule{Iterations: 2000}); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := x.Train(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ruleSet.Len() != 4 {
+		t.Fatalf("population has %v classifiers, want one per state and action", ruleSet.Len())
+	}
+	return ruleSet
+}
+
+// TestMultiStepUpdatesTowardsBestPrediction checks that the first step
+// is updated towards gamma times the best prediction of the second
+// (Q-learning), even though every action is exploratory.
+func TestMultiStepUpdatesTowardsBestPrediction(t *testing.T) {
+	gamma := xcs.DefaultParams().Gamma
+	want := map[string]float64{"0:0": gamma * 1000, "0:1": gamma * 1000, "1:0": 1000, "1:1": 0}
+	for _, cl := range trainChain(t, &chain{}).Classifiers() {
+		key := fmt.Sprintf("%v:%v", cl.GetCondition(), cl.GetAction())
+		if math.Abs(cl.GetPayoff()-want[key]) > 10 {
+			t.Errorf("classifier %v predicts %v, want %v", key, cl.GetPayoff(), want[key])
+		}
+	}
+}
+
+// TestMultiStepLeavesCutOffEpisodes checks that the last action set of
+// an episode that was cut off is not updated as if it had ended.
+func TestMultiStepLeavesCutOffEpisodes(t *testing.T) {
+	for _, cl := range trainChain(t, &chain{cutOff: true}).Classifiers() {
+		updated := cl.GetExperience() > 0
+		if first := cl.GetCondition().String() == "0"; updated != first {
+			t.Errorf("classifier %v:%v has experience %v", cl.GetCondition(), cl.GetAction(), cl.GetExperience())
+		}
+	}
+}
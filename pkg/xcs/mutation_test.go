@@ -0,0 +1,89 @@
+package xcs_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/maze"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// mutationShares mutates each symbol of a condition of 1000 copies of
+// symbol, for an input of 0s, and returns the share that became '#', '0'
+// and '1'.
+func mutationShares(t *testing.T, symbol byte, mutation string, pGeneralize float64) map[byte]float64 {
+	t.Helper()
+	params := xcs.DefaultParams()
+	params.Mu = 1
+	params.Mutation = mutation
+	params.PGeneralize = pGeneralize
+	s := make([]byte, 1000)
+	for i := range s {
+		s[i] = symbol
+	}
+	condition, err := xcs.ParseTernary(string(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition.Mutate(&maze.Percept{Inputs: make([]int, len(s))}, &params, rng.New(1))
+	shares := make(map[byte]float64)
+	for _, c := range []byte(condition.String()) {
+		shares[c] += 1.0 / float64(len(s))
+	}
+	return shares
+}
+
+func TestMutateSymbols(t *testing.T) {
+	tests := []struct {
+		symbol      byte
+		mutation    string
+		pGeneralize float64
+		want        map[byte]float64
+	}{
+		// Niche mutation keeps the condition matching the input.
+		{'0', xcs.MutationNiche, 0.5, map[byte]float64{'#': 1}},
+		{'#', xcs.MutationNiche, 0.5, map[byte]float64{'0': 1}},
+		{'0', xcs.MutationNiche, 0.25, map[byte]float64{'#': 0.5, '0': 0.5}},
+		{'#', xcs.MutationNiche, 0.75, map[byte]float64{'#': 0.5, '0': 0.5}},
+		// Free mutation always changes a specified symbol.
+		{'0', xcs.MutationFree, 0.5, map[byte]float64{'#': 0.5, '1': 0.5}},
+		{'#', xcs.MutationFree, 0.5, map[byte]float64{'0': 0.5, '1': 0.5}},
+		{'1', xcs.MutationFree, 0.25, map[byte]float64{'#': 0.25, '0': 0.75}},
+		{'#', xcs.MutationFree, 0.75, map[byte]float64{'#': 0.5, '0': 0.25, '1': 0.25}},
+	}
+	for _, test := range tests {
+		got := mutationShares(t, test.symbol, test.mutation, test.pGeneralize)
+		for _, c := range []byte("#01") {
+			if math.Abs(got[c]-test.want[c]) > 0.05 {
+				t.Errorf("%v mutation of %q with pGeneralize %v: %.3f became %q, want %.3f", test.mutation, test.symbol, test.pGeneralize, got[c], c, test.want[c])
+			}
+		}
+	}
+}
+
+func TestActionMutationRate(t *testing.T) {
+	for _, rates := range [][2]float64{{1, 0}, {0, 1}} {
+		params := xcs.DefaultParams()
+		params.Mu, params.MuAction = rates[0], rates[1]
+		params.MaxAction = 3
+		x, err := xcs.New(params, rng.New(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		input := &maze.Percept{Inputs: []int{0, 1, 0}}
+		for i := 0; i < 100; i++ {
+			condition, _ := conditionPair(t, "010")
+			cl := &xcs.Classifier{Condition: condition, Action: 2}
+			if err := x.ApplyMutation(cl, input); err != nil {
+				t.Fatal(err)
+			}
+			if actionMutated := cl.GetAction() != 2; actionMutated != (params.MuAction == 1) {
+				t.Fatalf("mu %v, muAction %v: action became %v", params.Mu, params.MuAction, cl.GetAction())
+			}
+			if conditionMutated := cl.GetCondition().String() != "010"; conditionMutated != (params.Mu == 1) {
+				t.Fatalf("mu %v, muAction %v: condition became %v", params.Mu, params.MuAction, cl.GetCondition())
+			}
+		}
+	}
+}
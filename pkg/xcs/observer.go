@@ -0,0 +1,177 @@
+package xcs
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+)
+
+// Observer receives events as a run progresses. Classifiers and
+// populations passed to an observer belong to the run and must not be
+// modified. Embed NopObserver to implement only some of the methods.
+type Observer interface {
+	IterationFinished(e IterationEvent)
+	EvaluationFinished(e EvaluationEvent)
+	GeneticAlgorithmInvoked(e GaEvent)
+	CoveringOccurred(e CoveringEvent)
+	ClassifierDeleted(e DeletionEvent)
+	SubsumptionOccurred(e SubsumptionEvent)
+	TrainingFinished(e TrainingEvent)
+}
+
+// IterationEvent reports the end of a training episode.
+type IterationEvent struct {
+	Iteration        int
+	Steps            int
+	TotalReward      float64
+	MacroClassifiers int
+}
+
+// EvaluationEvent reports the result of a periodic evaluation together
+// with the state of the population at that point. AverageGenerality is
+// the numerosity-weighted mean of Classifier.GetGenerality and
+// AverageFitness is the fitness sum divided by the micro-classifier
+// count.
+type EvaluationEvent struct {
+	MacroStep         int
+	Samples           int
+	Metric            mli.Metric
+	Value             float64
+	ProportionCorrect float64
+	AverageSteps      float64
+	AverageReward     float64
+	SystemError       float64
+	MacroClassifiers  int
+	MicroClassifiers  int32
+	AverageGenerality float64
+	AverageFitness    float64
+}
+
+// GaEvent reports a run of the genetic algorithm on an action set.
+type GaEvent struct {
+	Step             int64
+	ActionSetSize    int
+	Parent1, Parent2 *Classifier
+	Child1, Child2   *Classifier
+}
+
+// CoveringEvent reports a classifier created to cover a data item.
+type CoveringEvent struct {
+	Step       int64
+	Classifier *Classifier
+}
+
+// DeletionEvent reports a deletion from the population. Removed is true
+// if the macro-classifier left the population and false if only its
+// numerosity was decremented.
+type DeletionEvent struct {
+	Classifier *Classifier
+	Removed    bool
+}
+
+type SubsumptionKind int
+
+const (
+	GaSubsumption SubsumptionKind = iota
+	ActionSetSubsumption
+)
+
+func (k SubsumptionKind) String() string {
+	if k == GaSubsumption {
+		return "GA"
+	}
+	return "action set"
+}
+
+// SubsumptionEvent reports that Subsumer absorbed Subsumed, either a
+// child of the genetic algorithm or a member of an action set.
+type SubsumptionEvent struct {
+	Kind     SubsumptionKind
+	Subsumer *Classifier
+	Subsumed *Classifier
+}
+
+// TrainingEvent reports the end of a run with the evolved population.
+// Population is nil for algorithms whose classifiers are not those of
+// package xcs, such as ACS2.
+type TrainingEvent struct {
+	Iterations int
+	Population *Population
+}
+
+type NopObserver struct{}
+
+func (NopObserver) IterationFinished(e IterationEvent)     {}
+func (NopObserver) EvaluationFinished(e EvaluationEvent)   {}
+func (NopObserver) GeneticAlgorithmInvoked(e GaEvent)      {}
+func (NopObserver) CoveringOccurred(e CoveringEvent)       {}
+func (NopObserver) ClassifierDeleted(e DeletionEvent)      {}
+func (NopObserver) SubsumptionOccurred(e SubsumptionEvent) {}
+func (NopObserver) TrainingFinished(e TrainingEvent)       {}
+
+// ConsoleObserver writes evaluation results and, if PrintPopulation is
+// set, the final population to Writer. It is the default observer.
+type ConsoleObserver struct {
+	NopObserver
+	Writer          io.Writer
+	PrintPopulation bool
+}
+
+func (c *ConsoleObserver) EvaluationFinished(e EvaluationEvent) {
+	fmt.Fprintf(c.Writer, "Post-cycle eval #%v. %v: %v\n", e.MacroStep, e.Metric.Description(), e.Value)
+}
+
+func (c *ConsoleObserver) TrainingFinished(e TrainingEvent) {
+	if !c.PrintPopulation || e.Population == nil {
+		return
+	}
+	for _, cl := range e.Population.Classifiers() {
+		fmt.Fprintln(c.Writer, cl.ToString())
+	}
+}
+
+// Observers passes every event to each of its members in turn.
+type Observers []Observer
+
+func (o Observers) IterationFinished(e IterationEvent) {
+	for _, ob := range o {
+		ob.IterationFinished(e)
+	}
+}
+
+func (o Observers) EvaluationFinished(e EvaluationEvent) {
+	for _, ob := range o {
+		ob.EvaluationFinished(e)
+	}
+}
+
+func (o Observers) GeneticAlgorithmInvoked(e GaEvent) {
+	for _, ob := range o {
+		ob.GeneticAlgorithmInvoked(e)
+	}
+}
+
+func (o Observers) CoveringOccurred(e CoveringEvent) {
+	for _, ob := range o {
+		ob.CoveringOccurred(e)
+	}
+}
+
+func (o Observers) ClassifierDeleted(e DeletionEvent) {
+	for _, ob := range o {
+		ob.ClassifierDeleted(e)
+	}
+}
+
+func (o Observers) SubsumptionOccurred(e SubsumptionEvent) {
+	for _, ob := range o {
+		ob.SubsumptionOccurred(e)
+	}
+}
+
+func (o Observers) TrainingFinished(e TrainingEvent) {
+	for _, ob := range o {
+		ob.TrainingFinished(e)
+	}
+}
@@ -0,0 +1,254 @@
+package xcs
+
+import (
+	"fmt"
+	"math"
+)
+
+// Params holds the learning parameters of an XCS run. The names follow
+// Butz and Wilson (2000); DefaultParams returns the values used there.
+type Params struct {
+	Beta                 float64 `json:"beta" yaml:"beta" toml:"beta"`
+	PHash                float64 `json:"pHash" yaml:"pHash" toml:"pHash"`
+	Nu                   float64 `json:"nu" yaml:"nu" toml:"nu"`
+	PExplore             float64 `json:"pExplore" yaml:"pExplore" toml:"pExplore"`
+	MaxPop               int     `json:"maxPop" yaml:"maxPop" toml:"maxPop"`
+	DoGaSubsumption      bool    `json:"doGaSubsumption" yaml:"doGaSubsumption" toml:"doGaSubsumption"`
+	ThetaGa              int     `json:"thetaGa" yaml:"thetaGa" toml:"thetaGa"`
+	Chi                  float64 `json:"chi" yaml:"chi" toml:"chi"`
+	Mu                   float64 `json:"mu" yaml:"mu" toml:"mu"`
+	MaxAction            int     `json:"maxAction" yaml:"maxAction" toml:"maxAction"`
+	ErrorZero            float64 `json:"errorZero" yaml:"errorZero" toml:"errorZero"`
+	ThetaSub             int     `json:"thetaSub" yaml:"thetaSub" toml:"thetaSub"`
+	ThetaMna             int     `json:"thetaMna" yaml:"thetaMna" toml:"thetaMna"`
+	Epsilon0             float64 `json:"epsilon0" yaml:"epsilon0" toml:"epsilon0"`
+	Alpha                float64 `json:"alpha" yaml:"alpha" toml:"alpha"`
+	ThetaDel             int     `json:"thetaDel" yaml:"thetaDel" toml:"thetaDel"`
+	Delta                float64 `json:"delta" yaml:"delta" toml:"delta"`
+	Gamma                float64 `json:"gamma" yaml:"gamma" toml:"gamma"`
+	ActionSetSubsumption bool    `json:"actionSetSubsumption" yaml:"actionSetSubsumption" toml:"actionSetSubsumption"`
+	FitnessI             float64 `json:"fitnessI" yaml:"fitnessI" toml:"fitnessI"`
+	InitialError         float64 `json:"initialError" yaml:"initialError" toml:"initialError"`
+
+	// Selection chooses the parents of the genetic algorithm by roulette
+	// wheel in proportion to fitness or by tournament (XCSTS; Butz et
+	// al., 2003). In a tournament each micro-classifier of the action set
+	// takes part with probability TournamentSize and the entrant with the
+	// highest fitness per micro-classifier wins.
+	Selection      string  `json:"selection" yaml:"selection" toml:"selection"`
+	TournamentSize float64 `json:"tournamentSize" yaml:"tournamentSize" toml:"tournamentSize"`
+
+	// Crossover chooses the positions the offspring exchange: each
+	// position with probability 0.5 (uniform), those after one point,
+	// those between two points, or those in every other segment between
+	// CrossoverPoints distinct points (n-point). Conditions with fewer
+	// than CrossoverPoints+1 positions are cut at every position.
+	Crossover       string `json:"crossover" yaml:"crossover" toml:"crossover"`
+	CrossoverPoints int    `json:"crossoverPoints" yaml:"crossoverPoints" toml:"crossoverPoints"`
+
+	// Mutation selects niche mutation, under which a mutated ternary
+	// condition still matches the current input, or free mutation, under
+	// which a specified symbol may also flip to the other value and '#'
+	// becomes either value. PGeneralize biases mutation towards '#' (1)
+	// or towards specified symbols (0); at 0.5 it is unbiased. The action
+	// mutates with probability MuAction, which defaults to Mu.
+	Mutation    string  `json:"mutation" yaml:"mutation" toml:"mutation"`
+	PGeneralize float64 `json:"pGeneralize" yaml:"pGeneralize" toml:"pGeneralize"`
+	MuAction    float64 `json:"muAction" yaml:"muAction" toml:"muAction"`
+
+	// Condition selects ternary conditions over binary inputs (stored as
+	// strings, or as bitsets for faster matching), interval conditions
+	// over real inputs (XCSR) or integer interval conditions (XCSI). The
+	// remaining fields only apply to interval conditions: covering draws
+	// spreads of at most SpreadLimit, mutation moves an allele by at most
+	// MutationSpread and intervals are clipped to [InputMin, InputMax].
+	// Integer conditions always use lower and upper bounds and require
+	// whole numbers for these four fields.
+	Condition        string  `json:"condition" yaml:"condition" toml:"condition"`
+	IntervalEncoding string  `json:"intervalEncoding" yaml:"intervalEncoding" toml:"intervalEncoding"`
+	SpreadLimit      float64 `json:"spreadLimit" yaml:"spreadLimit" toml:"spreadLimit"`
+	MutationSpread   float64 `json:"mutationSpread" yaml:"mutationSpread" toml:"mutationSpread"`
+	InputMin         float64 `json:"inputMin" yaml:"inputMin" toml:"inputMin"`
+	InputMax         float64 `json:"inputMax" yaml:"inputMax" toml:"inputMax"`
+
+	// Prediction selects the scalar payoff of XCS or a prediction
+	// computed from the inputs (XCSF), either linear and trained with the
+	// modified delta rule at rate Eta or trained by recursive least
+	// squares. X0 is the constant input of the computed prediction,
+	// RlsDelta scales the initial RLS matrix and RlsLambda is the RLS
+	// forgetting factor.
+	Prediction string  `json:"prediction" yaml:"prediction" toml:"prediction"`
+	X0         float64 `json:"x0" yaml:"x0" toml:"x0"`
+	Eta        float64 `json:"eta" yaml:"eta" toml:"eta"`
+	RlsDelta   float64 `json:"rlsDelta" yaml:"rlsDelta" toml:"rlsDelta"`
+	RlsLambda  float64 `json:"rlsLambda" yaml:"rlsLambda" toml:"rlsLambda"`
+}
+
+const (
+	ConditionTernary = "ternary"
+	ConditionBitset  = "bitset"
+	ConditionReal    = "real"
+	ConditionInteger = "integer"
+
+	EncodingCenterSpread = "center-spread"
+	EncodingLowerUpper   = "lower-upper"
+
+	PredictionConstant = "constant"
+	PredictionLinear   = "linear"
+	PredictionRls      = "rls"
+
+	SelectionRoulette   = "roulette"
+	SelectionTournament = "tournament"
+
+	CrossoverUniform  = "uniform"
+	CrossoverOnePoint = "one-point"
+	CrossoverTwoPoint = "two-point"
+	CrossoverNPoint   = "n-point"
+
+	MutationNiche = "niche"
+	MutationFree  = "free"
+)
+
+// DefaultParams returns the parameter values given by Butz and Wilson
+// (2000). The settings of the later extensions default to the behaviour
+// of plain XCS.
+func DefaultParams() Params {
+	return Params{
+		Beta:                 0.2,
+		PHash:                0.33,
+		Nu:                   5.0,
+		PExplore:             0.5,
+		MaxPop:               400,
+		DoGaSubsumption:      true,
+		ThetaGa:              50,
+		Chi:                  0.8,
+		Mu:                   0.04,
+		MaxAction:            1,
+		ErrorZero:            10,
+		ThetaSub:             20,
+		ThetaMna:             2,
+		Epsilon0:             0.001,
+		Alpha:                0.1,
+		ThetaDel:             20,
+		Delta:                0.1,
+		Gamma:                0.71,
+		ActionSetSubsumption: false,
+		FitnessI:             0.0,
+		InitialError:         0.0,
+		Selection:            SelectionRoulette,
+		TournamentSize:       0.4,
+		Crossover:            CrossoverTwoPoint,
+		CrossoverPoints:      2,
+		Mutation:             MutationNiche,
+		PGeneralize:          0.5,
+		MuAction:             0.04,
+		Condition:            ConditionTernary,
+		IntervalEncoding:     EncodingCenterSpread,
+		SpreadLimit:          1.0,
+		MutationSpread:       0.1,
+		InputMin:             0.0,
+		InputMax:             1.0,
+		Prediction:           PredictionConstant,
+		X0:                   1.0,
+		Eta:                  0.2,
+		RlsDelta:             100,
+		RlsLambda:            1.0,
+	}
+}
+
+// Validate reports the first parameter that lies outside its permitted
+// range.
+func (p Params) Validate() error {
+	switch {
+	case p.Beta <= 0 || p.Beta > 1:
+		return InvalidParam("beta", p.Beta, "must be in (0, 1]")
+	case p.PHash < 0 || p.PHash > 1:
+		return InvalidParam("pHash", p.PHash, "must be in [0, 1]")
+	case p.Nu <= 0:
+		return InvalidParam("nu", p.Nu, "must be greater than 0")
+	case p.PExplore < 0 || p.PExplore > 1:
+		return InvalidParam("pExplore", p.PExplore, "must be in [0, 1]")
+	case p.MaxPop < 1:
+		return InvalidParam("maxPop", p.MaxPop, "must be at least 1")
+	case p.ThetaGa < 1:
+		return InvalidParam("thetaGa", p.ThetaGa, "must be at least 1")
+	case p.Chi < 0 || p.Chi > 1:
+		return InvalidParam("chi", p.Chi, "must be in [0, 1]")
+	case p.Mu < 0 || p.Mu > 1:
+		return InvalidParam("mu", p.Mu, "must be in [0, 1]")
+	case p.MaxAction < 0:
+		return InvalidParam("maxAction", p.MaxAction, "must not be negative")
+	case p.ErrorZero <= 0:
+		return InvalidParam("errorZero", p.ErrorZero, "must be greater than 0")
+	case p.ThetaSub < 0:
+		return InvalidParam("thetaSub", p.ThetaSub, "must not be negative")
+	case p.ThetaMna < 1 || p.ThetaMna > p.MaxAction+1:
+		return InvalidParam("thetaMna", p.ThetaMna, fmt.Sprintf("must be in [1, %v] (maxAction + 1)", p.MaxAction+1))
+	case p.Epsilon0 < 0:
+		return InvalidParam("epsilon0", p.Epsilon0, "must not be negative")
+	case p.Alpha <= 0 || p.Alpha > 1:
+		return InvalidParam("alpha", p.Alpha, "must be in (0, 1]")
+	case p.ThetaDel < 0:
+		return InvalidParam("thetaDel", p.ThetaDel, "must not be negative")
+	case p.Delta < 0 || p.Delta > 1:
+		return InvalidParam("delta", p.Delta, "must be in [0, 1]")
+	case p.Gamma < 0 || p.Gamma >= 1:
+		return InvalidParam("gamma", p.Gamma, "must be in [0, 1)")
+	case p.FitnessI < 0:
+		return InvalidParam("fitnessI", p.FitnessI, "must not be negative")
+	case p.InitialError < 0:
+		return InvalidParam("initialError", p.InitialError, "must not be negative")
+	case p.Selection != SelectionRoulette && p.Selection != SelectionTournament:
+		return InvalidParam("selection", p.Selection, fmt.Sprintf("must be %q or %q", SelectionRoulette, SelectionTournament))
+	case p.TournamentSize <= 0 || p.TournamentSize > 1:
+		return InvalidParam("tournamentSize", p.TournamentSize, "must be in (0, 1]")
+	case p.Crossover != CrossoverUniform && p.Crossover != CrossoverOnePoint && p.Crossover != CrossoverTwoPoint && p.Crossover != CrossoverNPoint:
+		return InvalidParam("crossover", p.Crossover, fmt.Sprintf("must be %q, %q, %q or %q", CrossoverUniform, CrossoverOnePoint, CrossoverTwoPoint, CrossoverNPoint))
+	case p.CrossoverPoints < 1:
+		return InvalidParam("crossoverPoints", p.CrossoverPoints, "must be at least 1")
+	case p.Mutation != MutationNiche && p.Mutation != MutationFree:
+		return InvalidParam("mutation", p.Mutation, fmt.Sprintf("must be %q or %q", MutationNiche, MutationFree))
+	case p.PGeneralize < 0 || p.PGeneralize > 1:
+		return InvalidParam("pGeneralize", p.PGeneralize, "must be in [0, 1]")
+	case p.MuAction < 0 || p.MuAction > 1:
+		return InvalidParam("muAction", p.MuAction, "must be in [0, 1]")
+	case p.Condition != ConditionTernary && p.Condition != ConditionBitset && p.Condition != ConditionReal && p.Condition != ConditionInteger:
+		return InvalidParam("condition", p.Condition, fmt.Sprintf("must be %q, %q, %q or %q", ConditionTernary, ConditionBitset, ConditionReal, ConditionInteger))
+	case p.IntervalEncoding != EncodingCenterSpread && p.IntervalEncoding != EncodingLowerUpper:
+		return InvalidParam("intervalEncoding", p.IntervalEncoding, fmt.Sprintf("must be %q or %q", EncodingCenterSpread, EncodingLowerUpper))
+	case p.SpreadLimit <= 0:
+		return InvalidParam("spreadLimit", p.SpreadLimit, "must be greater than 0")
+	case p.MutationSpread < 0:
+		return InvalidParam("mutationSpread", p.MutationSpread, "must not be negative")
+	case p.InputMax <= p.InputMin:
+		return InvalidParam("inputMax", p.InputMax, fmt.Sprintf("must be greater than inputMin (%v)", p.InputMin))
+	case p.Condition == ConditionInteger && !isWhole(p.SpreadLimit):
+		return InvalidParam("spreadLimit", p.SpreadLimit, "must be a whole number for integer conditions")
+	case p.Condition == ConditionInteger && !isWhole(p.MutationSpread):
+		return InvalidParam("mutationSpread", p.MutationSpread, "must be a whole number for integer conditions")
+	case p.Condition == ConditionInteger && !isWhole(p.InputMin):
+		return InvalidParam("inputMin", p.InputMin, "must be a whole number for integer conditions")
+	case p.Condition == ConditionInteger && !isWhole(p.InputMax):
+		return InvalidParam("inputMax", p.InputMax, "must be a whole number for integer conditions")
+	case p.Prediction != PredictionConstant && p.Prediction != PredictionLinear && p.Prediction != PredictionRls:
+		return InvalidParam("prediction", p.Prediction, fmt.Sprintf("must be %q, %q or %q", PredictionConstant, PredictionLinear, PredictionRls))
+	case p.Eta <= 0 || p.Eta > 1:
+		return InvalidParam("eta", p.Eta, "must be in (0, 1]")
+	case p.RlsDelta <= 0:
+		return InvalidParam("rlsDelta", p.RlsDelta, "must be greater than 0")
+	case p.RlsLambda <= 0 || p.RlsLambda > 1:
+		return InvalidParam("rlsLambda", p.RlsLambda, "must be in (0, 1]")
+	}
+	return nil
+}
+
+func isWhole(v float64) bool {
+	return v == math.Trunc(v)
+}
+
+// InvalidParam returns the error reported for a parameter outside its
+// permitted range, which wraps ErrInvalidParam. Packages building on xcs
+// use it for their own parameters.
+func InvalidParam(name string, value interface{}, reason string) error {
+	return fmt.Errorf("%w %v = %v: %v", ErrInvalidParam, name, value, reason)
+}
@@ -0,0 +1,25 @@
+package xcs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+func TestInvalidParamsWrapSentinel(t *testing.T) {
+	params := xcs.DefaultParams()
+	params.Beta = 2
+	if _, err := xcs.New(params, rng.New(1)); !errors.Is(err, xcs.ErrInvalidParam) {
+		t.Errorf("New with beta = 2 returned %v", err)
+	}
+	schedule := xcs.DefaultSchedule()
+	schedule.Iterations = -1
+	if err := schedule.Validate(); !errors.Is(err, xcs.ErrInvalidParam) {
+		t.Errorf("Validate with iterations = -1 returned %v", err)
+	}
+	if err := xcs.DefaultParams().Validate(); err != nil {
+		t.Errorf("default parameters rejected: %v", err)
+	}
+}
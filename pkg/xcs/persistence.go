@@ -0,0 +1,423 @@
+package xcs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FormatVersion is the version written by Save. Load accepts this
+// version and any earlier one.
+const FormatVersion = 3
+
+// binaryMagic starts every population in the binary format.
+const binaryMagic = "XCSP"
+
+// Algorithm is the name Save records for populations evolved by Xcs.
+// Algorithms built on package xcs record their own names with
+// SaveWithAlgorithm.
+const Algorithm = "xcs"
+
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatBinary
+)
+
+// FormatForPath returns FormatJSON for paths ending in .json and
+// FormatBinary otherwise.
+func FormatForPath(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return FormatJSON
+	}
+	return FormatBinary
+}
+
+type savedPopulation struct {
+	Version int `json:"version"`
+	// Algorithm names the algorithm that evolved the population. Added
+	// in version 3; earlier populations were all evolved by XCS.
+	Algorithm   string             `json:"algorithm,omitempty"`
+	Params      Params             `json:"params"`
+	Classifiers []classifierRecord `json:"classifiers"`
+}
+
+type classifierRecord struct {
+	Condition       string  `json:"condition"`
+	Action          int     `json:"action"`
+	Payoff          float64 `json:"payoff"`
+	PredictionError float64 `json:"predictionError"`
+	Fitness         float64 `json:"fitness"`
+	Numerosity      int32   `json:"numerosity"`
+	ActionSetSize   float64 `json:"actionSetSize"`
+	Exp             int64   `json:"exp"`
+	TimeStamp       int64   `json:"timeStamp"`
+
+	// Intervals holds the condition of a real-valued classifier, whose
+	// Condition is empty. Added in version 2.
+	Intervals []Interval `json:"intervals,omitempty"`
+
+	// Weights and RlsMatrix hold the computed prediction of an XCSF
+	// classifier. Added in version 3.
+	Weights   []float64 `json:"weights,omitempty"`
+	RlsMatrix []float64 `json:"rlsMatrix,omitempty"`
+}
+
+// binaryRecord is the fixed-size part of a classifier in the binary
+// format; the condition precedes it as a length-prefixed string.
+type binaryRecord struct {
+	Action          int32
+	Payoff          float64
+	PredictionError float64
+	Fitness         float64
+	Numerosity      int32
+	ActionSetSize   float64
+	Exp             int64
+	TimeStamp       int64
+}
+
+// toRecord converts a classifier for saving. Conditions other than
+// IntervalCondition are stored as their String form, which Load reads
+// as a BitsetCondition or TernaryCondition according to the parameters.
+func toRecord(cl *Classifier) classifierRecord {
+	r := classifierRecord{
+		Action:          cl.GetAction(),
+		Payoff:          cl.GetPayoff(),
+		PredictionError: cl.GetPredictionError(),
+		Fitness:         cl.GetFitness(),
+		Numerosity:      cl.GetNumerosity(),
+		ActionSetSize:   cl.GetActionSetSize(),
+		Exp:             cl.GetExperience(),
+		TimeStamp:       cl.GetTimeStamp(),
+		Weights:         copyFloats(cl.GetWeights()),
+		RlsMatrix:       copyFloats(cl.RlsMatrix),
+	}
+	if intervals, ok := cl.GetCondition().(IntervalCondition); ok {
+		r.Intervals = append([]Interval{}, intervals...)
+	} else {
+		r.Condition = cl.GetCondition().String()
+	}
+	return r
+}
+
+func fromRecord(p Params, r classifierRecord) (*Classifier, error) {
+	var condition Condition
+	if r.Intervals != nil {
+		if r.Condition != "" {
+			return nil, fmt.Errorf("classifier has both a ternary condition %q and intervals", r.Condition)
+		}
+		for i, iv := range r.Intervals {
+			if iv.Lower > iv.Upper {
+				return nil, fmt.Errorf("invalid interval %v: lower bound %v exceeds upper bound %v", i, iv.Lower, iv.Upper)
+			}
+		}
+		condition = append(IntervalCondition{}, r.Intervals...)
+	} else if p.Condition == ConditionBitset {
+		bitset, err := ParseBitset(r.Condition)
+		if err != nil {
+			return nil, err
+		}
+		condition = bitset
+	} else {
+		ternary, err := ParseTernary(r.Condition)
+		if err != nil {
+			return nil, err
+		}
+		condition = ternary
+	}
+	cl := newClassifier(p, condition, r.Action, r.TimeStamp)
+	if r.Weights != nil && len(r.Weights) != condition.Len()+1 {
+		return nil, fmt.Errorf("classifier has %v weights, expected %v", len(r.Weights), condition.Len()+1)
+	}
+	if r.RlsMatrix != nil && len(r.RlsMatrix) != len(r.Weights)*len(r.Weights) {
+		return nil, fmt.Errorf("classifier has an RLS matrix of %v entries, expected %v", len(r.RlsMatrix), len(r.Weights)*len(r.Weights))
+	}
+	cl.Weights = copyFloats(r.Weights)
+	cl.RlsMatrix = copyFloats(r.RlsMatrix)
+	cl.SetPayoff(r.Payoff)
+	cl.SetPredictionError(r.PredictionError)
+	cl.SetFitness(r.Fitness)
+	cl.SetNumerosity(r.Numerosity)
+	cl.SetActionSetSize(r.ActionSetSize)
+	cl.SetExperience(r.Exp)
+	return cl, nil
+}
+
+// Save writes the population and the parameters used to evolve it.
+func Save(w io.Writer, format Format, params Params, ruleSet *Population) error {
+	return SaveWithAlgorithm(w, format, Algorithm, params, ruleSet)
+}
+
+// SaveWithAlgorithm is Save for a population evolved by the named
+// algorithm, such as "ucs" or "zcs", which Load then requires.
+func SaveWithAlgorithm(w io.Writer, format Format, algorithm string, params Params, ruleSet *Population) error {
+	records := make([]classifierRecord, 0, ruleSet.Len())
+	for _, cl := range ruleSet.Classifiers() {
+		records = append(records, toRecord(cl))
+	}
+	saved := savedPopulation{FormatVersion, algorithm, params, records}
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(saved)
+	case FormatBinary:
+		return writeBinary(w, saved)
+	}
+	return fmt.Errorf("unknown population format %v", format)
+}
+
+// Load reads a population written by Save in either format. It returns
+// ErrWrongAlgorithm for a population evolved by another algorithm; use
+// LoadWithAlgorithm to read those.
+func Load(r io.Reader) (Params, *Population, error) {
+	algorithm, params, ruleSet, err := LoadWithAlgorithm(r)
+	if err != nil {
+		return Params{}, nil, err
+	}
+	if algorithm != Algorithm {
+		return Params{}, nil, fmt.Errorf("%w: %v", ErrWrongAlgorithm, algorithm)
+	}
+	return params, ruleSet, nil
+}
+
+// LoadWithAlgorithm reads a population written by Save or
+// SaveWithAlgorithm and returns the name of the algorithm that evolved
+// it along with the population.
+func LoadWithAlgorithm(r io.Reader) (string, Params, *Population, error) {
+	br := bufio.NewReader(r)
+	saved := savedPopulation{Params: DefaultParams()}
+	magic, err := br.Peek(len(binaryMagic))
+	if err == nil && string(magic) == binaryMagic {
+		saved, err = readBinary(br)
+	} else {
+		err = json.NewDecoder(br).Decode(&saved)
+	}
+	if err != nil {
+		return "", Params{}, nil, err
+	}
+	if saved.Version < 1 || saved.Version > FormatVersion {
+		return "", Params{}, nil, fmt.Errorf("unsupported population format version %v", saved.Version)
+	}
+	if saved.Algorithm == "" {
+		saved.Algorithm = Algorithm
+	}
+	if err := saved.Params.Validate(); err != nil {
+		return "", Params{}, nil, err
+	}
+	ruleSet := NewPopulation()
+	for _, r := range saved.Classifiers {
+		cl, err := fromRecord(saved.Params, r)
+		if err != nil {
+			return "", Params{}, nil, err
+		}
+		ruleSet.Add(cl)
+	}
+	return saved.Algorithm, saved.Params, ruleSet, nil
+}
+
+func SaveFile(path string, params Params, ruleSet *Population) error {
+	return SaveFileWithAlgorithm(path, Algorithm, params, ruleSet)
+}
+
+func SaveFileWithAlgorithm(path string, algorithm string, params Params, ruleSet *Population) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := SaveWithAlgorithm(f, FormatForPath(path), algorithm, params, ruleSet); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func LoadFile(path string) (Params, *Population, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Params{}, nil, err
+	}
+	defer f.Close()
+	params, ruleSet, err := Load(f)
+	if err != nil {
+		return Params{}, nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return params, ruleSet, nil
+}
+
+func LoadFileWithAlgorithm(path string) (string, Params, *Population, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", Params{}, nil, err
+	}
+	defer f.Close()
+	algorithm, params, ruleSet, err := LoadWithAlgorithm(f)
+	if err != nil {
+		return "", Params{}, nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return algorithm, params, ruleSet, nil
+}
+
+// The binary format is: the magic string, the version (uint16), from
+// version 3 the algorithm as a length-prefixed string, the parameters as
+// length-prefixed JSON, the number of classifiers (uint32)
+// and then each classifier as its length-prefixed condition, from
+// version 2 a uvarint interval count and that many lower and upper bound
+// pairs (float64), from version 3 the weights and the RLS matrix as
+// uvarint-counted float64 lists, and finally a binaryRecord. All
+// integers are little-endian.
+func writeBinary(w io.Writer, saved savedPopulation) error {
+	bw := bufio.NewWriter(w)
+	paramsJSON, err := json.Marshal(saved.Params)
+	if err != nil {
+		return err
+	}
+	bw.WriteString(binaryMagic)
+	binary.Write(bw, binary.LittleEndian, uint16(saved.Version))
+	writeBytes(bw, []byte(saved.Algorithm))
+	writeBytes(bw, paramsJSON)
+	binary.Write(bw, binary.LittleEndian, uint32(len(saved.Classifiers)))
+	for _, r := range saved.Classifiers {
+		writeBytes(bw, []byte(r.Condition))
+		writeUvarint(bw, uint64(len(r.Intervals)))
+		if err := binary.Write(bw, binary.LittleEndian, r.Intervals); err != nil {
+			return err
+		}
+		for _, values := range [][]float64{r.Weights, r.RlsMatrix} {
+			writeUvarint(bw, uint64(len(values)))
+			if err := binary.Write(bw, binary.LittleEndian, values); err != nil {
+				return err
+			}
+		}
+		rec := binaryRecord{int32(r.Action), r.Payoff, r.PredictionError, r.Fitness, r.Numerosity, r.ActionSetSize, r.Exp, r.TimeStamp}
+		if err := binary.Write(bw, binary.LittleEndian, rec); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func readBinary(r *bufio.Reader) (savedPopulation, error) {
+	saved := savedPopulation{Params: DefaultParams()}
+	if _, err := r.Discard(len(binaryMagic)); err != nil {
+		return saved, err
+	}
+	var version uint16
+	if err := binary.Read(r, binary.LittleEndian, &version); err != nil {
+		return saved, truncated(err)
+	}
+	saved.Version = int(version)
+	if saved.Version >= 3 {
+		algorithm, err := readBytes(r)
+		if err != nil {
+			return saved, err
+		}
+		saved.Algorithm = string(algorithm)
+	}
+	paramsJSON, err := readBytes(r)
+	if err != nil {
+		return saved, err
+	}
+	if err := json.Unmarshal(paramsJSON, &saved.Params); err != nil {
+		return saved, err
+	}
+	var count uint32
+	if err := binary.Read(r, binary.LittleEndian, &count); err != nil {
+		return saved, truncated(err)
+	}
+	for i := uint32(0); i < count; i++ {
+		condition, err := readBytes(r)
+		if err != nil {
+			return saved, err
+		}
+		var intervals []Interval
+		if saved.Version >= 2 {
+			n, err := readCount(r)
+			if err != nil {
+				return saved, err
+			}
+			if n > 0 {
+				intervals = make([]Interval, n)
+				if err := binary.Read(r, binary.LittleEndian, intervals); err != nil {
+					return saved, truncated(err)
+				}
+			}
+		}
+		var weights, rlsMatrix []float64
+		if saved.Version >= 3 {
+			if weights, err = readFloats(r); err != nil {
+				return saved, err
+			}
+			if rlsMatrix, err = readFloats(r); err != nil {
+				return saved, err
+			}
+		}
+		var rec binaryRecord
+		if err := binary.Read(r, binary.LittleEndian, &rec); err != nil {
+			return saved, truncated(err)
+		}
+		saved.Classifiers = append(saved.Classifiers, classifierRecord{string(condition), int(rec.Action), rec.Payoff, rec.PredictionError, rec.Fitness, rec.Numerosity, rec.ActionSetSize, rec.Exp, rec.TimeStamp, intervals, weights, rlsMatrix})
+	}
+	return saved, nil
+}
+
+func writeBytes(w *bufio.Writer, b []byte) {
+	writeUvarint(w, uint64(len(b)))
+	w.Write(b)
+}
+
+func writeUvarint(w *bufio.Writer, v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	w.Write(buf[:n])
+}
+
+func readCount(r *bufio.Reader) (int, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, truncated(err)
+	}
+	if n > math.MaxInt32 {
+		return 0, fmt.Errorf("field length %v is too large", n)
+	}
+	return int(n), nil
+}
+
+func readFloats(r *bufio.Reader) ([]float64, error) {
+	n, err := readCount(r)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+	values := make([]float64, n)
+	if err := binary.Read(r, binary.LittleEndian, values); err != nil {
+		return nil, truncated(err)
+	}
+	return values, nil
+}
+
+func readBytes(r *bufio.Reader) ([]byte, error) {
+	n, err := readCount(r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, truncated(err)
+	}
+	return b, nil
+}
+
+func truncated(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return errors.New("population data is truncated")
+	}
+	return err
+}
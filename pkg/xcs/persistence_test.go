@@ -0,0 +1,144 @@
+package xcs_test
+
+import (
+	"bytes"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// trainMultiplexer evolves a population on the 6-bit multiplexer, real
+// valued if the condition is.
+func trainMultiplexer(t *testing.T, params xcs.Params, iterations int) *xcs.Population {
+	t.Helper()
+	rnd := rng.New(1)
+	newProblem := multiplexer.New
+	if params.Condition == xcs.ConditionReal {
+		newProblem = multiplexer.NewReal
+	}
+	problem, err := newProblem(6, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, err := xcs.New(params, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.SetObserver(xcs.NopObserver{})
+	if err := x.SetSchedule(xcs.Schedule{Iterations: iterations}); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := x.Train(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ruleSet
+}
+
+func encode(t *testing.T, format xcs.Format, params xcs.Params, ruleSet *xcs.Population) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := xcs.Save(&buf, format, params, ruleSet); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	for _, condition := range []string{xcs.ConditionTernary, xcs.ConditionBitset, xcs.ConditionReal} {
+		params := xcs.DefaultParams()
+		params.Condition = condition
+		ruleSet := trainMultiplexer(t, params, 2000)
+		for _, format := range []xcs.Format{xcs.FormatJSON, xcs.FormatBinary} {
+			saved := encode(t, format, params, ruleSet)
+			gotParams, got, err := xcs.Load(bytes.NewReader(saved))
+			if err != nil {
+				t.Fatalf("%v, format %v: %v", condition, format, err)
+			}
+			if !reflect.DeepEqual(gotParams, params) {
+				t.Errorf("%v, format %v: params = %+v, want %+v", condition, format, gotParams, params)
+			}
+			if got.Len() != ruleSet.Len() || got.MicroSize() != ruleSet.MicroSize() {
+				t.Errorf("%v, format %v: loaded %v classifiers (%v micro), want %v (%v)", condition, format, got.Len(), got.MicroSize(), ruleSet.Len(), ruleSet.MicroSize())
+			}
+			if resaved := encode(t, format, gotParams, got); !bytes.Equal(resaved, saved) {
+				t.Errorf("%v, format %v: population changed in a save and load", condition, format)
+			}
+		}
+	}
+}
+
+func TestSaveFileFormat(t *testing.T) {
+	params := xcs.DefaultParams()
+	ruleSet := trainMultiplexer(t, params, 500)
+	dir := t.TempDir()
+	for _, name := range []string{"pop.json", "pop.bin"} {
+		path := filepath.Join(dir, name)
+		if err := xcs.SaveFile(path, params, ruleSet); err != nil {
+			t.Fatal(err)
+		}
+		_, got, err := xcs.LoadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Len() != ruleSet.Len() {
+			t.Errorf("%v: loaded %v classifiers, want %v", name, got.Len(), ruleSet.Len())
+		}
+	}
+}
+
+func TestSaveLoadAlgorithm(t *testing.T) {
+	params := xcs.DefaultParams()
+	ruleSet := trainMultiplexer(t, params, 500)
+	for _, format := range []xcs.Format{xcs.FormatJSON, xcs.FormatBinary} {
+		var buf bytes.Buffer
+		if err := xcs.SaveWithAlgorithm(&buf, format, "ucs", params, ruleSet); err != nil {
+			t.Fatal(err)
+		}
+		saved := buf.Bytes()
+		algorithm, _, got, err := xcs.LoadWithAlgorithm(bytes.NewReader(saved))
+		if err != nil {
+			t.Fatalf("format %v: %v", format, err)
+		}
+		if algorithm != "ucs" || got.Len() != ruleSet.Len() {
+			t.Errorf("format %v: loaded %v classifiers of %q, want %v of %q", format, got.Len(), algorithm, ruleSet.Len(), "ucs")
+		}
+		if _, _, err := xcs.Load(bytes.NewReader(saved)); !errors.Is(err, xcs.ErrWrongAlgorithm) {
+			t.Errorf("format %v: Load of a UCS population returned %v", format, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownVersion(t *testing.T) {
+	_, _, err := xcs.Load(strings.NewReader(`{"version": 99, "classifiers": []}`))
+	if err == nil || !strings.Contains(err.Error(), "version 99") {
+		t.Errorf("Load of version 99: err = %v, want an unsupported version error", err)
+	}
+}
+
+func TestLoadVersion1(t *testing.T) {
+	// Version 1 files have no intervals or weights.
+	const saved = `{"version": 1, "classifiers": [
+		{"condition": "01#1##", "action": 1, "payoff": 1000, "predictionError": 0,
+		 "fitness": 0.9, "numerosity": 3, "actionSetSize": 12, "exp": 40, "timeStamp": 7}]}`
+	params, ruleSet, err := xcs.Load(strings.NewReader(saved))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params, xcs.DefaultParams()) {
+		t.Errorf("params = %+v, want the defaults", params)
+	}
+	if ruleSet.Len() != 1 {
+		t.Fatalf("loaded %v classifiers, want 1", ruleSet.Len())
+	}
+	cl := ruleSet.Classifiers()[0]
+	if cl.GetCondition().String() != "01#1##" || cl.GetAction() != 1 || cl.GetNumerosity() != 3 || cl.GetExperience() != 40 {
+		t.Errorf("loaded %v", cl.ToString())
+	}
+}
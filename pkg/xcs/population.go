@@ -0,0 +1,149 @@
+package xcs
+
+import "strconv"
+
+// Population is the set of macro-classifiers of a run. It keeps an index
+// on (condition, action) so that duplicates are found without a scan,
+// and caches the micro-classifier count and the fitness sum. Change the
+// numerosity or fitness of a member through AddNumerosity and SetFitness
+// so that the cached values stay correct, and do not modify the
+// condition or action of a member.
+type Population struct {
+	classifiers []*Classifier
+	positions   map[*Classifier]int
+	index       map[string][]*Classifier
+	micro       int32
+	fitnessSum  float64
+	updates     int
+}
+
+// resumInterval is the number of fitness updates after which the cached
+// fitness sum is summed afresh, so that rounding errors cannot build up
+// over a long run.
+const resumInterval = 1 << 16
+
+func NewPopulation() *Population {
+	return &Population{
+		positions: make(map[*Classifier]int),
+		index:     make(map[string][]*Classifier),
+	}
+}
+
+func populationKey(condition Condition, action int) string {
+	return strconv.Itoa(action) + ":" + condition.String()
+}
+
+func (p *Population) Len() int {
+	return len(p.classifiers)
+}
+
+// Classifiers returns the members of the population. The slice belongs
+// to the population and is only valid until it next changes.
+func (p *Population) Classifiers() []*Classifier {
+	return p.classifiers
+}
+
+// MicroSize returns the sum of the numerosities of the members.
+func (p *Population) MicroSize() int32 {
+	return p.micro
+}
+
+// FitnessSum returns the sum of the fitnesses of the members.
+func (p *Population) FitnessSum() float64 {
+	return p.fitnessSum
+}
+
+func (p *Population) Contains(cl *Classifier) bool {
+	_, ok := p.positions[cl]
+	return ok
+}
+
+// Add appends cl without looking for a duplicate; see Find.
+func (p *Population) Add(cl *Classifier) {
+	if p.Contains(cl) {
+		return
+	}
+	p.positions[cl] = len(p.classifiers)
+	p.classifiers = append(p.classifiers, cl)
+	key := populationKey(cl.Condition, cl.Action)
+	p.index[key] = append(p.index[key], cl)
+	p.micro += cl.Numerosity
+	p.fitnessSum += cl.Fitness
+}
+
+// Find returns a member with the given condition and action, or nil.
+func (p *Population) Find(condition Condition, action int) *Classifier {
+	for _, cl := range p.index[populationKey(condition, action)] {
+		if cl.Condition.Equal(condition) {
+			return cl
+		}
+	}
+	return nil
+}
+
+// Remove takes cl out of the population in constant time by moving the
+// last member into its place. It reports whether cl was a member.
+func (p *Population) Remove(cl *Classifier) bool {
+	i, ok := p.positions[cl]
+	if !ok {
+		return false
+	}
+	last := len(p.classifiers) - 1
+	p.classifiers[i] = p.classifiers[last]
+	p.positions[p.classifiers[i]] = i
+	p.classifiers[last] = nil
+	p.classifiers = p.classifiers[:last]
+	delete(p.positions, cl)
+
+	key := populationKey(cl.Condition, cl.Action)
+	same := p.index[key]
+	for j, c := range same {
+		if c == cl {
+			same = append(same[:j], same[j+1:]...)
+			break
+		}
+	}
+	if len(same) == 0 {
+		delete(p.index, key)
+	} else {
+		p.index[key] = same
+	}
+	p.micro -= cl.Numerosity
+	p.fitnessSum -= cl.Fitness
+	if len(p.classifiers) == 0 {
+		p.fitnessSum = 0
+	}
+	return true
+}
+
+// AddNumerosity changes the numerosity of cl by n. cl need not be a
+// member, in which case only cl changes.
+func (p *Population) AddNumerosity(cl *Classifier, n int32) {
+	cl.IncrementNumerosityBy(n)
+	if p.Contains(cl) {
+		p.micro += n
+	}
+}
+
+// SetFitness sets the fitness of cl, which need not be a member.
+func (p *Population) SetFitness(cl *Classifier, fitness float64) {
+	if !p.Contains(cl) {
+		cl.SetFitness(fitness)
+		return
+	}
+	p.fitnessSum += fitness - cl.Fitness
+	cl.SetFitness(fitness)
+	p.updates++
+	if p.updates == resumInterval {
+		p.resum()
+	}
+}
+
+// resum recomputes the cached fitness sum from the members.
+func (p *Population) resum() {
+	p.fitnessSum = 0
+	for _, cl := range p.classifiers {
+		p.fitnessSum += cl.Fitness
+	}
+	p.updates = 0
+}
@@ -0,0 +1,52 @@
+package xcs_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+func TestPopulationCachesSums(t *testing.T) {
+	rnd := rng.New(1)
+	ruleSet := xcs.NewPopulation()
+	var members []*xcs.Classifier
+	for i := 0; i < 50; i++ {
+		condition, _ := conditionPair(t, randomTernary(6, rnd))
+		cl := &xcs.Classifier{Condition: condition, Action: i % 2, Numerosity: 1, Fitness: rnd.Float64()}
+		ruleSet.Add(cl)
+		members = append(members, cl)
+	}
+	for i := 0; i < 200000; i++ {
+		cl := members[rnd.Intn(len(members))]
+		switch i % 1000 {
+		case 0:
+			ruleSet.Remove(cl)
+		case 1:
+			ruleSet.Add(cl)
+		default:
+			ruleSet.SetFitness(cl, rnd.Float64())
+			ruleSet.AddNumerosity(cl, 1)
+		}
+	}
+
+	fitnessSum, micro := 0.0, int32(0)
+	for _, cl := range ruleSet.Classifiers() {
+		fitnessSum += cl.GetFitness()
+		micro += cl.GetNumerosity()
+	}
+	if math.Abs(ruleSet.FitnessSum()-fitnessSum) > 1e-9 {
+		t.Errorf("cached fitness sum %v, want %v", ruleSet.FitnessSum(), fitnessSum)
+	}
+	if ruleSet.MicroSize() != micro {
+		t.Errorf("cached micro-classifier count %v, want %v", ruleSet.MicroSize(), micro)
+	}
+
+	for _, cl := range members {
+		ruleSet.Remove(cl)
+	}
+	if ruleSet.FitnessSum() != 0 || ruleSet.MicroSize() != 0 {
+		t.Errorf("empty population has fitness sum %v and %v micro-classifiers", ruleSet.FitnessSum(), ruleSet.MicroSize())
+	}
+}
@@ -0,0 +1,106 @@
+package xcs
+
+import "github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+
+// The computed prediction of XCSF follows Wilson (2002) for the linear
+// predictor and Lanzi et al. (2006) for recursive least squares. A
+// classifier's prediction is w[0]*X0 + w[1]*s[0] + ... + w[n]*s[n-1]
+// for the inputs s; classifiers with no weights predict their Payoff.
+
+// predictorInputs returns the inputs a computed prediction is taken
+// over, or nil if predictions are constant.
+func (x *Xcs) predictorInputs(dataItem mli.DataItem) []float64 {
+	if x.params.Prediction == PredictionConstant {
+		return nil
+	}
+	return realInputs(dataItem)
+}
+
+// prediction returns the prediction of cl for inputs obtained from
+// predictorInputs.
+func (x *Xcs) prediction(cl *Classifier, inputs []float64) float64 {
+	if cl.Weights == nil {
+		return cl.GetPayoff()
+	}
+	p := cl.Weights[0] * x.params.X0
+	for i, v := range inputs {
+		p += cl.Weights[i+1] * v
+	}
+	return p
+}
+
+// initPredictor gives a covering classifier zero weights and, for
+// recursive least squares, a fresh matrix.
+func (x *Xcs) initPredictor(cl *Classifier, numInputs int) {
+	if x.params.Prediction == PredictionConstant {
+		return
+	}
+	cl.Weights = make([]float64, numInputs+1)
+	x.resetRls(cl)
+}
+
+// resetRls sets the RLS matrix of cl to RlsDelta times the identity. The
+// offspring of the genetic algorithm keep their parent's weights but
+// start with a fresh matrix, since their conditions may have changed.
+func (x *Xcs) resetRls(cl *Classifier) {
+	if x.params.Prediction != PredictionRls {
+		return
+	}
+	n := len(cl.Weights)
+	cl.RlsMatrix = make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		cl.RlsMatrix[i*n+i] = x.params.RlsDelta
+	}
+}
+
+// updatePredictor moves the weights of cl towards target and returns the
+// new prediction for the inputs.
+func (x *Xcs) updatePredictor(cl *Classifier, inputs []float64, target float64) float64 {
+	xs := make([]float64, len(inputs)+1)
+	xs[0] = x.params.X0
+	copy(xs[1:], inputs)
+	err := target - x.prediction(cl, inputs)
+	if cl.RlsMatrix != nil {
+		x.updateRls(cl, xs, err)
+	} else {
+		norm := 0.0
+		for _, v := range xs {
+			norm += v * v
+		}
+		if norm > 0 {
+			for i, v := range xs {
+				cl.Weights[i] += x.params.Eta / norm * err * v
+			}
+		}
+	}
+	return x.prediction(cl, inputs)
+}
+
+// updateRls applies one step of recursive least squares: with gain
+// k = F*s / (lambda + s'*F*s) the weights move by k*err and F becomes
+// (F - k*s'*F) / lambda.
+func (x *Xcs) updateRls(cl *Classifier, xs []float64, err float64) {
+	n := len(xs)
+	f := cl.RlsMatrix
+	fx := make([]float64, n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			fx[i] += f[i*n+j] * xs[j]
+		}
+	}
+	denom := x.params.RlsLambda
+	for i := 0; i < n; i++ {
+		denom += xs[i] * fx[i]
+	}
+	gain := make([]float64, n)
+	for i := 0; i < n; i++ {
+		gain[i] = fx[i] / denom
+		cl.Weights[i] += gain[i] * err
+	}
+	// F is symmetric, so s'*F is the transpose of F*s.
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			f[i*n+j] = (f[i*n+j] - gain[i]*fx[j]) / x.params.RlsLambda
+		}
+	}
+}
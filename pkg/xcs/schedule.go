@@ -0,0 +1,53 @@
+package xcs
+
+import (
+	"fmt"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+)
+
+// Schedule controls how long a run lasts and how often the population
+// is evaluated while it is being trained.
+type Schedule struct {
+	Iterations   int `json:"iterations" yaml:"iterations" toml:"iterations"`
+	EvalInterval int `json:"evalInterval" yaml:"evalInterval" toml:"evalInterval"`
+	EvalSamples  int `json:"evalSamples" yaml:"evalSamples" toml:"evalSamples"`
+	// CheckpointInterval is the number of iterations between checkpoints.
+	// Zero disables checkpointing.
+	CheckpointInterval int `json:"checkpointInterval" yaml:"checkpointInterval" toml:"checkpointInterval"`
+	// Metric chooses how evaluations score the population. Empty means
+	// the metric advertised by the problem (see mli.MetricProblem).
+	Metric mli.Metric `json:"metric" yaml:"metric" toml:"metric"`
+	// EvalStepCap ends evaluation episodes after this many steps; an
+	// episode that fails to reach the goal counts as taking EvalStepCap
+	// steps. Zero leaves episodes to end by themselves.
+	EvalStepCap int `json:"evalStepCap" yaml:"evalStepCap" toml:"evalStepCap"`
+}
+
+func DefaultSchedule() Schedule {
+	return Schedule{
+		Iterations:   80001,
+		EvalInterval: 50,
+		EvalSamples:  100,
+	}
+}
+
+// Validate reports the first schedule setting that lies outside its
+// permitted range. An EvalInterval of zero disables evaluation.
+func (s Schedule) Validate() error {
+	switch {
+	case s.Iterations < 0:
+		return InvalidParam("iterations", s.Iterations, "must not be negative")
+	case s.EvalInterval < 0:
+		return InvalidParam("evalInterval", s.EvalInterval, "must not be negative")
+	case s.EvalInterval > 0 && s.EvalSamples < 1:
+		return InvalidParam("evalSamples", s.EvalSamples, "must be at least 1 when evaluation is enabled")
+	case s.CheckpointInterval < 0:
+		return InvalidParam("checkpointInterval", s.CheckpointInterval, "must not be negative")
+	case s.EvalStepCap < 0:
+		return InvalidParam("evalStepCap", s.EvalStepCap, "must not be negative")
+	case s.Metric != "" && s.Metric != mli.MetricAccuracy && s.Metric != mli.MetricSteps && s.Metric != mli.MetricReward && s.Metric != mli.MetricSystemError:
+		return InvalidParam("metric", s.Metric, fmt.Sprintf("must be empty, %q, %q, %q or %q", mli.MetricAccuracy, mli.MetricSteps, mli.MetricReward, mli.MetricSystemError))
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package xcs_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/config"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// selectionShares returns the share of 20000 selections from actionSet
+// won by each of its classifiers.
+func selectionShares(t *testing.T, params xcs.Params, actionSet []*xcs.Classifier) []float64 {
+	t.Helper()
+	x, err := xcs.New(params, rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const draws = 20000
+	shares := make([]float64, len(actionSet))
+	for i := 0; i < draws; i++ {
+		cl, err := x.SelectOffspring(actionSet)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for j, c := range actionSet {
+			if c == cl {
+				shares[j] += 1.0 / draws
+			}
+		}
+	}
+	return shares
+}
+
+func checkShares(t *testing.T, name string, got, want []float64) {
+	t.Helper()
+	for i := range want {
+		if math.Abs(got[i]-want[i]) > 0.015 {
+			t.Errorf("%v: classifier %v won %.3f of the selections, want %.3f", name, i, got[i], want[i])
+		}
+	}
+}
+
+// TestTournamentSelection selects from an action set in which the
+// classifier with the highest fitness is the least fit per
+// micro-classifier.
+func TestTournamentSelection(t *testing.T) {
+	condition, _ := conditionPair(t, "0#1")
+	actionSet := []*xcs.Classifier{
+		{Condition: condition, Fitness: 0.9, Numerosity: 3},
+		{Condition: condition, Fitness: 0.4, Numerosity: 1},
+		{Condition: condition, Fitness: 0.1, Numerosity: 1},
+	}
+
+	cfg, err := config.Parse([]byte("xcs:\n  selection: tournament\n  tournamentSize: 0.4\n"), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each micro-classifier enters with probability 0.4 and a round
+	// with no entrant is held again. The second classifier wins whenever
+	// it enters; the first needs one of its three micro-classifiers to.
+	none := 0.6 * 0.216 * 0.6
+	want := []float64{0.6 * (1 - 0.216) / (1 - none), 0.4 / (1 - none), 0.6 * 0.216 * 0.4 / (1 - none)}
+	checkShares(t, "tournament of 0.4", selectionShares(t, cfg.Xcs, actionSet), want)
+
+	cfg.Xcs.TournamentSize = 1
+	checkShares(t, "tournament of 1", selectionShares(t, cfg.Xcs, actionSet), []float64{0, 1, 0})
+
+	cfg.Xcs.Selection = xcs.SelectionRoulette
+	checkShares(t, "roulette", selectionShares(t, cfg.Xcs, actionSet), []float64{0.9 / 1.4, 0.4 / 1.4, 0.1 / 1.4})
+}
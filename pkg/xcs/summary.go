@@ -0,0 +1,38 @@
+package xcs
+
+// Summary describes a population in terms of macro-classifiers (distinct
+// rules) and micro-classifiers (rules weighted by numerosity).
+type Summary struct {
+	MacroClassifiers int
+	MicroClassifiers int32
+	AverageFitness   float64
+	AverageError     float64
+	AverageExp       float64
+	// AverageGenerality is the numerosity-weighted mean of
+	// Classifier.GetGenerality.
+	AverageGenerality float64
+	MacroPerAction    map[int]int
+}
+
+func Summarize(ruleSet *Population) Summary {
+	s := Summary{MacroPerAction: make(map[int]int)}
+	fitnessSum, errorSum, expSum, generalitySum := 0.0, 0.0, 0.0, 0.0
+	for _, cl := range ruleSet.Classifiers() {
+		num := float64(cl.GetNumerosity())
+		s.MacroClassifiers++
+		s.MicroClassifiers += cl.GetNumerosity()
+		s.MacroPerAction[cl.GetAction()]++
+		fitnessSum += cl.GetFitness()
+		errorSum += cl.GetPredictionError() * num
+		expSum += float64(cl.GetExperience()) * num
+		generalitySum += cl.GetGenerality() * num
+	}
+	if s.MicroClassifiers > 0 {
+		micro := float64(s.MicroClassifiers)
+		s.AverageFitness = fitnessSum / micro
+		s.AverageError = errorSum / micro
+		s.AverageExp = expSum / micro
+		s.AverageGenerality = generalitySum / micro
+	}
+	return s
+}
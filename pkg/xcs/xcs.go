@@ -6,278 +6,280 @@
 package xcs
 
 import (
-	"container/list"
+	"errors"
 	"fmt"
 	"log"
 	"math"
-	"math/rand"
-	"strconv"
+	"os"
+	"sort"
 
 	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
-)
-
-const (
-	beta                 = 0.2
-	pHash                = 0.33
-	nu                   = 5.0
-	pExplore             = 0.5
-	maxPop               = 400
-	doGaSubsumption      = true
-	thetaGa              = 50
-	chi                  = 0.8
-	mu                   = 0.04
-	maxAction            = 1
-	errorZero            = 10
-	thetaSub             = 20
-	thetaMna             = maxAction + 1
-	epsilon0             = 0.001
-	alpha                = 0.1
-	thetaDel             = 20
-	delta                = 0.1
-	gamma                = 0.71
-	actionSetSubsumption = false
-	fitnessI             = 0.0
-	initialError         = 0.0
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
 )
 
 type Xcs struct {
+	params        Params
+	schedule      Schedule
+	rnd           *rng.Rand
+	observer      Observer
+	onCheckpoint  func(cp *Checkpoint) error
+	conditionType Condition
+	predictions   PredictionFunc
 }
 
-func (x *Xcs) RuleMatchesState(rule *Classifier, state mli.DataItem) bool {
-	condition := rule.GetCondition()
-	inputs := state.GetInputs()
-	numAttributes := len(inputs)
-	for idx := 0; idx < numAttributes; idx++ {
-		if condition[idx] == "0" {
-			if inputs[idx] != 0 {
-				return false
-			}
-		} else if condition[idx] == "1" {
-			if inputs[idx] != 1 {
-				return false
-			}
-		}
-	}
-	return true
+// New returns an Xcs that draws all of its random numbers from rnd. Two
+// runs given generators with the same seed, and problems drawing from
+// the same generators, produce identical results.
+func New(params Params, rnd *rng.Rand) (*Xcs, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	if rnd == nil {
+		return nil, errors.New("a random number generator is required")
+	}
+	return &Xcs{
+		params:   params,
+		schedule: DefaultSchedule(),
+		rnd:      rnd,
+		observer: &ConsoleObserver{Writer: os.Stdout, PrintPopulation: true},
+	}, nil
 }
 
-func (x *Xcs) CreateMatchSet(ruleSet *list.List, dataItem mli.DataItem, step int64) *list.List {
-	matchSet := x.ObtainMatchingClassifiers(ruleSet, dataItem)
-	for matchSet.Len() < thetaMna {
-		cl := x.GenerateClassifier(matchSet, dataItem, step)
-		ruleSet.PushBack(cl)
-		x.DeleteFromPop(ruleSet)
-		matchSet.PushBack(cl)
+// SetObserver replaces the observer notified of run events. The default
+// is a ConsoleObserver writing to standard output; nil silences the run.
+func (x *Xcs) SetObserver(observer Observer) {
+	if observer == nil {
+		observer = NopObserver{}
 	}
-	return matchSet
+	x.observer = observer
 }
 
-func (x *Xcs) ObtainMatchingClassifiers(ruleSet *list.List, dataItem mli.DataItem) *list.List {
-	matchSet := list.New()
-	for r := ruleSet.Front(); r != nil; r = r.Next() {
-		if x.RuleMatchesState(r.Value.(*Classifier), dataItem) {
-			matchSet.PushBack(r.Value.(*Classifier))
-		}
-	}
-	return matchSet
+func (x *Xcs) GetParams() Params {
+	return x.params
 }
 
-func (x *Xcs) GenerateClassifier(matchSet *list.List, dataItem mli.DataItem, step int64) *Classifier {
-	condition := make([]string, len(dataItem.GetInputs()))
-	for i, attrib := range dataItem.GetInputs() {
-		if rand.Float64() < pHash {
-			condition[i] = "#"
-		} else {
-			condition[i] = strconv.Itoa(attrib)
-		}
+func (x *Xcs) GetSchedule() Schedule {
+	return x.schedule
+}
+
+func (x *Xcs) SetSchedule(schedule Schedule) error {
+	if err := schedule.Validate(); err != nil {
+		return err
 	}
-	actionsPresent := make(map[int]bool, maxAction-1)
-	allActions := list.New()
-	for i := 0; i <= maxAction; i++ {
-		allActions.PushBack(i)
+	x.schedule = schedule
+	return nil
+}
+
+// SetConditionType replaces the condition encoding selected by
+// Params.Condition: covering calls Cover on prototype, usually the zero
+// value of a Condition type. Passing nil restores the default.
+func (x *Xcs) SetConditionType(prototype Condition) {
+	x.conditionType = prototype
+}
+
+func (x *Xcs) getConditionType() Condition {
+	if x.conditionType != nil {
+		return x.conditionType
 	}
-	for i := 0; i <= maxAction; i++ {
-		actionsPresent[i] = false
+	switch x.params.Condition {
+	case ConditionBitset:
+		return BitsetCondition{}
+	case ConditionReal, ConditionInteger:
+		return IntervalCondition(nil)
 	}
+	return TernaryCondition(nil)
+}
 
-	for e := matchSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
-		act := cl.GetAction()
-		actionsPresent[act] = true
+func (x *Xcs) RuleMatchesState(rule *Classifier, state mli.DataItem) bool {
+	return rule.GetCondition().Matches(state)
+}
+
+func (x *Xcs) CreateMatchSet(ruleSet *Population, dataItem mli.DataItem, step int64) ([]*Classifier, error) {
+	matchSet, err := x.ObtainMatchingClassifiers(ruleSet, dataItem)
+	if err != nil {
+		return nil, err
+	}
+	for len(matchSet) < x.params.ThetaMna {
+		cl, err := x.GenerateClassifier(matchSet, dataItem, step)
+		if err != nil {
+			return nil, err
+		}
+		ruleSet.Add(cl)
+		x.observer.CoveringOccurred(CoveringEvent{step, cl})
+		x.DeleteFromPop(ruleSet)
+		matchSet = append(matchSet, cl)
 	}
+	return matchSet, nil
+}
 
-	toChooseFrom := list.New()
-	for i := 0; i <= maxAction; i++ {
-		if actionsPresent[i] == false {
-			toChooseFrom.PushBack(i)
+// ObtainMatchingClassifiers returns the classifiers of ruleSet matching
+// the data item. It fails with ErrConditionLength if a condition and the
+// inputs differ in length.
+func (x *Xcs) ObtainMatchingClassifiers(ruleSet *Population, dataItem mli.DataItem) ([]*Classifier, error) {
+	var matchSet []*Classifier
+	numInputs := len(dataItem.GetInputs())
+	if _, ok := x.getConditionType().(BitsetCondition); ok {
+		dataItem = packInput(dataItem)
+	}
+	for _, cl := range ruleSet.Classifiers() {
+		if cl.GetCondition().Len() != numInputs {
+			return nil, fmt.Errorf("%w: classifier %v has %v attributes, data item has %v",
+				ErrConditionLength, cl.ToString(), cl.GetCondition().Len(), numInputs)
+		}
+		if x.RuleMatchesState(cl, dataItem) {
+			matchSet = append(matchSet, cl)
 		}
 	}
+	return matchSet, nil
+}
 
-	if toChooseFrom.Len() == 0 {
-		toChooseFrom = allActions
+func (x *Xcs) GenerateClassifier(matchSet []*Classifier, dataItem mli.DataItem, step int64) (*Classifier, error) {
+	condition, err := x.getConditionType().Cover(dataItem, &x.params, x.rnd)
+	if err != nil {
+		return nil, err
+	}
+	maxAction := x.params.MaxAction
+	actionsPresent := make([]bool, maxAction+1)
+	for _, cl := range matchSet {
+		if action := cl.GetAction(); action >= 0 && action <= maxAction {
+			actionsPresent[action] = true
+		}
 	}
 
-	randomIdx := rand.Intn(toChooseFrom.Len())
-	currentIdx := 0
-	answer := -1
-	for e := toChooseFrom.Front(); e != nil; e = e.Next() {
-		if currentIdx == randomIdx {
-			answer = e.Value.(int)
-			break
+	var toChooseFrom []int
+	for i := 0; i <= maxAction; i++ {
+		if !actionsPresent[i] {
+			toChooseFrom = append(toChooseFrom, i)
 		}
-		currentIdx++
 	}
-	if answer == -1 {
-		log.Fatal("Error. answer == -1.")
+	if len(toChooseFrom) == 0 {
+		for i := 0; i <= maxAction; i++ {
+			toChooseFrom = append(toChooseFrom, i)
+		}
+	}
+	if len(toChooseFrom) == 0 {
+		return nil, ErrNoAction
 	}
-	return &Classifier{condition, answer, 0.0, initialError, initialError, fitnessI, fitnessI, 1, 0, 0, thetaSub, step, nu, make([]int, 80000), thetaDel, delta, errorZero}
+
+	answer := toChooseFrom[x.rnd.Intn(len(toChooseFrom))]
+	cl := newClassifier(x.params, condition, answer, step)
+	x.initPredictor(cl, len(dataItem.GetInputs()))
+	return cl, nil
+}
+
+// CoverAction returns a new classifier advocating the given action whose
+// condition covers the data item, for algorithms such as UCS in which
+// the action of a covering classifier is known.
+func (x *Xcs) CoverAction(dataItem mli.DataItem, action int, step int64) (*Classifier, error) {
+	condition, err := x.getConditionType().Cover(dataItem, &x.params, x.rnd)
+	if err != nil {
+		return nil, err
+	}
+	cl := newClassifier(x.params, condition, action, step)
+	x.initPredictor(cl, len(dataItem.GetInputs()))
+	return cl, nil
 }
 
-func (x *Xcs) CountMicroClassifiers(ruleSet *list.List) int32 {
+func (x *Xcs) CountMicroClassifiers(set []*Classifier) int32 {
 	microPop := int32(0)
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cls := e.Value.(*Classifier)
+	for _, cls := range set {
 		microPop += cls.GetNumerosity()
 	}
 	return microPop
 }
 
-func (x *Xcs) GetAverageFitnessOfPop(ruleSet *list.List, microPopCount int32) float64 {
-	fitnessSum := 0.0
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cls := e.Value.(*Classifier)
-		fitnessSum += cls.GetFitness()
-	}
-	return fitnessSum / float64(microPopCount)
+func (x *Xcs) GetAverageFitnessOfPop(ruleSet *Population) float64 {
+	return ruleSet.FitnessSum() / float64(ruleSet.MicroSize())
 }
 
-func (x *Xcs) DeleteFromPop(ruleSet *list.List) {
-	microPop := x.CountMicroClassifiers(ruleSet)
-	if microPop < maxPop {
+func (x *Xcs) DeleteFromPop(ruleSet *Population) {
+	if ruleSet.MicroSize() < int32(x.params.MaxPop) {
 		return
 	}
-	averageFitnessOfPop := x.GetAverageFitnessOfPop(ruleSet, microPop)
+	averageFitnessOfPop := x.GetAverageFitnessOfPop(ruleSet)
 	voteSum := 0.0
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cls := e.Value.(*Classifier)
+	for _, cls := range ruleSet.Classifiers() {
 		voteSum += cls.GetDeletionVote(averageFitnessOfPop)
 	}
-	choicePoint := voteSum * rand.Float64()
+	choicePoint := voteSum * x.rnd.Float64()
 	voteSum = 0.0
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range ruleSet.Classifiers() {
 		voteSum += cl.GetDeletionVote(averageFitnessOfPop)
 		if voteSum > choicePoint {
 			if cl.GetNumerosity() > 1 {
-				cl.DecrementNumerosity()
+				ruleSet.AddNumerosity(cl, -1)
+				x.observer.ClassifierDeleted(DeletionEvent{cl, false})
 			} else {
-				ruleSet.Remove(e)
+				ruleSet.Remove(cl)
+				x.observer.ClassifierDeleted(DeletionEvent{cl, true})
 			}
 			break
 		}
 	}
 }
 
-func (x *Xcs) DoActionSetSubsumption(actionSet *list.List, ruleSet *list.List) {
+// DoActionSetSubsumption lets the most general experienced and accurate
+// classifier of the action set absorb those it is more general than, and
+// returns the action set without them.
+func (x *Xcs) DoActionSetSubsumption(actionSet []*Classifier, ruleSet *Population) []*Classifier {
 	var cl *Classifier
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		c := e.Value.(*Classifier)
+	for _, c := range actionSet {
 		if c.CouldSubsume() {
-			if cl == nil || c.GetHashCount() > cl.GetHashCount() || (c.GetHashCount() == cl.GetHashCount() && rand.Float64() > 0.5) {
+			if cl == nil || c.GetGenerality() > cl.GetGenerality() || (c.GetGenerality() == cl.GetGenerality() && x.rnd.Float64() > 0.5) {
 				cl = c
 			}
 		}
 	}
 
-	toDelete := list.New()
-	if cl != nil {
-		for e := actionSet.Front(); e != nil; e = e.Next() {
-			classifier := e.Value.(*Classifier)
-			if cl.IsMoreGeneralThan(classifier) {
-				toDelete.PushBack(e)
-			}
-		}
+	if cl == nil {
+		return actionSet
 	}
-	for e := toDelete.Front(); e != nil; e = e.Next() {
-		ruleSet.Remove(e)
-		actionSet.Remove(e)
-		td := e.Value.(*Classifier)
-		cl.IncrementNumerosityBy(td.GetNumerosity())
+	remaining := actionSet[:0:0]
+	for _, classifier := range actionSet {
+		if cl.IsMoreGeneralThan(classifier) {
+			ruleSet.AddNumerosity(cl, classifier.GetNumerosity())
+			ruleSet.Remove(classifier)
+			x.observer.SubsumptionOccurred(SubsumptionEvent{ActionSetSubsumption, cl, classifier})
+		} else {
+			remaining = append(remaining, classifier)
+		}
 	}
+	return remaining
 }
 
-func (x *Xcs) ApplyMutation(classifier *Classifier, dataItem mli.DataItem) {
-	condition := classifier.GetCondition()
-	for k := 0; k < len(condition); k++ {
-		if rand.Float64() < mu {
-			if condition[k] == "#" {
-				attrib := dataItem.GetAttribute(k)
-				classifier.SetConditionComponent(k, strconv.Itoa(attrib))
-			} else {
-				classifier.SetConditionComponent(k, "#")
-			}
-		}
-	}
-	if rand.Float64() < mu {
+func (x *Xcs) ApplyMutation(classifier *Classifier, dataItem mli.DataItem) error {
+	classifier.GetCondition().Mutate(dataItem, &x.params, x.rnd)
+	if x.rnd.Float64() < x.params.MuAction {
 		clAction := classifier.GetAction()
 		allActions := x.GetSetOfActionsLessSpecified(clAction)
-		randomIdx := rand.Intn(allActions.Len())
-		currentIdx := 0
-		action := -1
-		for e := allActions.Front(); e != nil; e = e.Next() {
-			if currentIdx == randomIdx {
-				action = e.Value.(int)
-				break
-			}
-			currentIdx++
-		}
-		if action == -1 {
-			log.Fatal("Error. action == -1.")
+		if len(allActions) == 0 {
+			return nil
 		}
-		classifier.SetAction(action)
+		classifier.SetAction(allActions[x.rnd.Intn(len(allActions))])
 	}
+	return nil
 }
 
-func (x *Xcs) GetSetOfActionsLessSpecified(action int) *list.List {
-	allActions := list.New()
-	for j := 0; j < thetaMna; j++ {
+func (x *Xcs) GetSetOfActionsLessSpecified(action int) []int {
+	var allActions []int
+	for j := 0; j <= x.params.MaxAction; j++ {
 		if j != action {
-			allActions.PushBack(j)
+			allActions = append(allActions, j)
 		}
 	}
 	return allActions
 }
 
-func (x *Xcs) InsertInPopulation(classifier *Classifier, ruleSet *list.List) {
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
-		if cl.DoesMatch(classifier) {
-			cl.IncrementNumerosity()
-			return
-		}
+func (x *Xcs) InsertInPopulation(classifier *Classifier, ruleSet *Population) {
+	if cl := ruleSet.Find(classifier.GetCondition(), classifier.GetAction()); cl != nil {
+		ruleSet.AddNumerosity(cl, 1)
+		return
 	}
-	ruleSet.PushBack(classifier)
+	ruleSet.Add(classifier)
 }
 
 func (xs *Xcs) ApplyCrossover(classifier1 *Classifier, classifier2 *Classifier) {
-	x := rand.Intn(len(classifier1.GetCondition()))
-	y := rand.Intn(len(classifier2.GetCondition()))
-	if x > y {
-		z := x
-		x = y
-		y = z
-	}
-	condition1 := classifier1.GetCondition()
-	condition2 := classifier2.GetCondition()
-
-	for m := 0; m < len(classifier1.GetCondition()); m++ {
-		if x <= m && m < y {
-			cc1 := condition1[m]
-			cc2 := condition2[m]
-			condition1[m] = cc2
-			condition2[m] = cc1
-		}
-	}
+	classifier1.GetCondition().Crossover(classifier2.GetCondition(), &xs.params, xs.rnd)
 
 	newFitness := (classifier1.GetFitness() + classifier2.GetFitness()) / 2
 	classifier1.SetFitness(newFitness)
@@ -292,62 +294,87 @@ func (xs *Xcs) ApplyCrossover(classifier1 *Classifier, classifier2 *Classifier)
 	classifier2.SetPayoff(newPayoff)
 }
 
-func (x *Xcs) SelectOffspring(actionSet *list.List) *Classifier {
-	if actionSet.Len() == 1 {
-		return actionSet.Front().Value.(*Classifier)
+// SelectOffspring chooses a parent from the action set by the method
+// named by Params.Selection.
+func (x *Xcs) SelectOffspring(actionSet []*Classifier) (*Classifier, error) {
+	if len(actionSet) == 0 {
+		return nil, ErrEmptyActionSet
+	}
+	if len(actionSet) == 1 {
+		return actionSet[0], nil
+	}
+	if x.params.Selection == SelectionTournament {
+		return x.selectByTournament(actionSet), nil
 	}
 	fitnessSum := 0.0
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range actionSet {
 		fitnessSum = fitnessSum + cl.GetFitness()
 	}
-	choicePoint := rand.Float64() * fitnessSum
+	choicePoint := x.rnd.Float64() * fitnessSum
 	fitnessSum = 0.0
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range actionSet {
 		fitnessSum = fitnessSum + cl.GetFitness()
 		if fitnessSum > choicePoint {
-			return cl
+			return cl, nil
 		}
 	}
+	return actionSet[x.rnd.Intn(len(actionSet))], nil
+}
 
-	randomIdx := rand.Intn(actionSet.Len())
-	currentIdx := 0
-	var cls *Classifier
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		if currentIdx == randomIdx {
-			cls = e.Value.(*Classifier)
-			break
+// selectByTournament holds tournaments among the micro-classifiers of
+// the action set, each taking part with probability TournamentSize,
+// until one has an entrant, and returns the entrant with the highest
+// fitness per micro-classifier.
+func (x *Xcs) selectByTournament(actionSet []*Classifier) *Classifier {
+	for {
+		var winner *Classifier
+		for _, cl := range actionSet {
+			if winner != nil && cl.GetFitness()/float64(cl.GetNumerosity()) <= winner.GetFitness()/float64(winner.GetNumerosity()) {
+				continue
+			}
+			for j := int32(0); j < cl.GetNumerosity(); j++ {
+				if x.rnd.Float64() < x.params.TournamentSize {
+					winner = cl
+					break
+				}
+			}
+		}
+		if winner != nil {
+			return winner
 		}
-		currentIdx++
-	}
-	if cls == nil {
-		log.Fatal("Error. cls == nil.")
 	}
-	return cls
 }
 
-func (x *Xcs) RunGeneticAlgorithm(actionSet *list.List, dataItem mli.DataItem, ruleSet *list.List, step int64) {
+func (x *Xcs) RunGeneticAlgorithm(actionSet []*Classifier, dataItem mli.DataItem, ruleSet *Population, step int64) error {
+	if len(actionSet) == 0 {
+		return ErrEmptyActionSet
+	}
 	numerositySum := int32(0)
 	timeStampSum := int64(0)
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range actionSet {
 		numerositySum = numerositySum + cl.GetNumerosity()
 		timeStampSum = timeStampSum + (cl.GetTimeStamp() * int64(cl.GetNumerosity()))
 	}
-	if float64(step)-float64(timeStampSum)/float64(numerositySum) > float64(thetaGa) {
+	if float64(step)-float64(timeStampSum)/float64(numerositySum) > float64(x.params.ThetaGa) {
 
-		for e := actionSet.Front(); e != nil; e = e.Next() {
-			cl := e.Value.(*Classifier)
+		for _, cl := range actionSet {
 			cl.SetTimeStamp(step)
 		}
 
-		parent1 := x.SelectOffspring(actionSet)
-		parent2 := x.SelectOffspring(actionSet)
+		parent1, err := x.SelectOffspring(actionSet)
+		if err != nil {
+			return err
+		}
+		parent2, err := x.SelectOffspring(actionSet)
+		if err != nil {
+			return err
+		}
 		child1 := parent1.GetOffspring()
 		child2 := parent2.GetOffspring()
+		x.resetRls(child1)
+		x.resetRls(child2)
 
-		if rand.Float64() < chi {
+		if x.rnd.Float64() < x.params.Chi {
 			x.ApplyCrossover(child1, child2)
 		}
 
@@ -355,12 +382,16 @@ func (x *Xcs) RunGeneticAlgorithm(actionSet *list.List, dataItem mli.DataItem, r
 		child2.SetFitness(child2.GetFitness() * 0.1)
 
 		for _, child := range []*Classifier{child1, child2} {
-			x.ApplyMutation(child, dataItem)
-			if doGaSubsumption {
+			if err := x.ApplyMutation(child, dataItem); err != nil {
+				return err
+			}
+			if x.params.DoGaSubsumption {
 				if parent1.DoesSubsume(child) {
-					parent1.IncrementNumerosity()
+					ruleSet.AddNumerosity(parent1, 1)
+					x.observer.SubsumptionOccurred(SubsumptionEvent{GaSubsumption, parent1, child})
 				} else if parent2.DoesSubsume(child) {
-					parent2.IncrementNumerosity()
+					ruleSet.AddNumerosity(parent2, 1)
+					x.observer.SubsumptionOccurred(SubsumptionEvent{GaSubsumption, parent2, child})
 				} else {
 					x.InsertInPopulation(child, ruleSet)
 				}
@@ -369,13 +400,32 @@ func (x *Xcs) RunGeneticAlgorithm(actionSet *list.List, dataItem mli.DataItem, r
 			}
 			x.DeleteFromPop(ruleSet)
 		}
+		x.observer.GeneticAlgorithmInvoked(GaEvent{step, len(actionSet), parent1, parent2, child1, child2})
 	}
+	return nil
 }
 
-func (x *Xcs) CreatePredictionArray(matchSet *list.List) map[int]float64 {
-	actionSet := make(map[int]bool, maxAction)
-	for e := matchSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+// PredictionFunc computes the prediction array of a match set for a
+// data item; see SetPredictionFunc.
+type PredictionFunc func(matchSet []*Classifier, dataItem mli.DataItem) map[int]float64
+
+// SetPredictionFunc replaces the computation of the prediction array,
+// and so the choice of action in exploitation and evaluation, by f. A
+// nil f restores the fitness-weighted average of XCS.
+func (x *Xcs) SetPredictionFunc(f PredictionFunc) {
+	x.predictions = f
+}
+
+// CreatePredictionArray returns the fitness-weighted average prediction
+// of each action advocated in the match set for the data item, unless
+// SetPredictionFunc installed another computation.
+func (x *Xcs) CreatePredictionArray(matchSet []*Classifier, dataItem mli.DataItem) map[int]float64 {
+	if x.predictions != nil {
+		return x.predictions(matchSet, dataItem)
+	}
+	inputs := x.predictorInputs(dataItem)
+	actionSet := make(map[int]bool, x.params.MaxAction+1)
+	for _, cl := range matchSet {
 		actionSet[cl.GetAction()] = true
 	}
 	pa := make(map[int]float64, len(actionSet))
@@ -383,14 +433,13 @@ func (x *Xcs) CreatePredictionArray(matchSet *list.List) map[int]float64 {
 	for k := range actionSet {
 		fsa[k] = 0.0
 	}
-	for e := matchSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range matchSet {
 		a := cl.GetAction()
 		_, exists := pa[a]
 		if !exists {
-			pa[a] = cl.GetPayoff() * cl.GetFitness()
+			pa[a] = x.prediction(cl, inputs) * cl.GetFitness()
 		} else {
-			pa[a] = pa[a] + cl.GetPayoff()*cl.GetFitness()
+			pa[a] = pa[a] + x.prediction(cl, inputs)*cl.GetFitness()
 		}
 		fsa[a] = fsa[a] + cl.GetFitness()
 	}
@@ -402,51 +451,51 @@ func (x *Xcs) CreatePredictionArray(matchSet *list.List) map[int]float64 {
 	return pa
 }
 
-func (x *Xcs) CreateActionSet(matchSet *list.List, action int) *list.List {
-	actionSet := list.New()
-	for e := matchSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+func (x *Xcs) CreateActionSet(matchSet []*Classifier, action int) []*Classifier {
+	var actionSet []*Classifier
+	for _, cl := range matchSet {
 		if cl.GetAction() == action {
-			actionSet.PushBack(cl)
+			actionSet = append(actionSet, cl)
 		}
 	}
 	return actionSet
 }
 
-func (x *Xcs) UpdateFitnessInSet(actionSet *list.List) {
+func (x *Xcs) UpdateFitnessInSet(actionSet []*Classifier, ruleSet *Population) {
 	accuracySum := 0.0
-	k := make([]float64, actionSet.Len())
-	i := 0
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	k := make([]float64, len(actionSet))
+	for i, cl := range actionSet {
 		var val float64
-		if cl.GetError() < errorZero {
+		if cl.GetError() < x.params.ErrorZero {
 			val = 1.0
 		} else {
-			val = alpha * (math.Pow((cl.GetError() / errorZero), -nu))
+			val = x.params.Alpha * (math.Pow((cl.GetError() / x.params.ErrorZero), -x.params.Nu))
 		}
 		k[i] = val
 		accuracySum += val * float64(cl.GetNumerosity())
-		i++
 	}
-	i = 0
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
-		fitness := cl.GetFitness() + beta*(k[i]*float64(cl.GetNumerosity())/accuracySum-cl.GetFitness())
-		cl.SetFitness(fitness)
-		i++
+	for i, cl := range actionSet {
+		fitness := cl.GetFitness() + x.params.Beta*(k[i]*float64(cl.GetNumerosity())/accuracySum-cl.GetFitness())
+		ruleSet.SetFitness(cl, fitness)
 	}
 }
 
-func (x *Xcs) UpdateActionSet(capitalP float64, actionSet *list.List, ruleSet *list.List) {
+// UpdateActionSet updates the classifiers of an action set formed for
+// the data item towards the payoff capitalP. With computed prediction,
+// Payoff holds the prediction for the data item after the update. It
+// returns the action set less any classifiers subsumed in the process.
+func (x *Xcs) UpdateActionSet(capitalP float64, actionSet []*Classifier, dataItem mli.DataItem, ruleSet *Population) []*Classifier {
+	beta := x.params.Beta
+	inputs := x.predictorInputs(dataItem)
 	totAsNum := x.CountMicroClassifiers(actionSet)
-	for e := actionSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
+	for _, cl := range actionSet {
 		cl.IncrementExperience()
 		classifierExp := cl.GetExperience()
 		inexperienced := float64(classifierExp) < 1/beta
 		payoff := cl.GetPayoff()
-		if inexperienced {
+		if cl.Weights != nil {
+			payoff = x.updatePredictor(cl, inputs, capitalP)
+		} else if inexperienced {
 			payoff += (capitalP - payoff) / float64(classifierExp)
 		} else {
 			payoff += beta * (capitalP - payoff)
@@ -467,117 +516,322 @@ func (x *Xcs) UpdateActionSet(capitalP float64, actionSet *list.List, ruleSet *l
 		}
 		cl.SetActionSetSize(actionSetSize)
 	}
-	x.UpdateFitnessInSet(actionSet)
-	if actionSetSubsumption {
-		x.DoActionSetSubsumption(actionSet, ruleSet)
+	x.UpdateFitnessInSet(actionSet, ruleSet)
+	if x.params.ActionSetSubsumption {
+		actionSet = x.DoActionSetSubsumption(actionSet, ruleSet)
 	}
+	return actionSet
 }
 
-func (x *Xcs) Evaluate(problem mli.Problem, ruleSet *list.List, macroStep int) {
+func (x *Xcs) Evaluate(problem mli.Problem, ruleSet *Population, macroStep int) error {
+	a, err := x.Assess(problem, ruleSet, x.schedule.EvalSamples)
+	if err != nil {
+		return err
+	}
+	summary := Summarize(ruleSet)
+	x.observer.EvaluationFinished(EvaluationEvent{
+		MacroStep:         macroStep,
+		Samples:           x.schedule.EvalSamples,
+		Metric:            a.Metric,
+		Value:             a.Value,
+		ProportionCorrect: a.ProportionCorrect,
+		AverageSteps:      a.AverageSteps,
+		AverageReward:     a.AverageReward,
+		SystemError:       a.SystemError,
+		MacroClassifiers:  summary.MacroClassifiers,
+		MicroClassifiers:  summary.MicroClassifiers,
+		AverageGenerality: summary.AverageGenerality,
+		AverageFitness:    summary.AverageFitness,
+	})
+	return nil
+}
+
+// Assessment is the outcome of exploiting a population on a number of
+// problem episodes. Metric is the measure that applies to the problem
+// and Value its value; the other measures are filled in as well.
+type Assessment struct {
+	Metric            mli.Metric
+	Value             float64
+	ProportionCorrect float64
+	// AverageSteps is the mean number of steps per episode, 1 for
+	// single-step problems such as the multiplexer. With an EvalStepCap,
+	// an episode that fails to reach the goal counts as the cap.
+	AverageSteps float64
+	// AverageReward is the mean total reward per episode.
+	AverageReward float64
+	// SystemError is the mean of |prediction - P| over the steps in
+	// which some classifier matched the input, where P is the reward
+	// plus, on all but the last step, gamma times the prediction for the
+	// next input.
+	SystemError float64
+}
+
+// Score exploits the population on the given number of problem episodes
+// and returns the proportion answered correctly. An episode in which no
+// classifier matches the input counts as incorrect.
+func (x *Xcs) Score(problem mli.Problem, ruleSet *Population, samples int) (float64, error) {
+	a, err := x.Assess(problem, ruleSet, samples)
+	return a.ProportionCorrect, err
+}
+
+// metric returns the metric set in the schedule, or else the one
+// advertised by the problem, or else accuracy.
+func (x *Xcs) metric(problem mli.Problem) mli.Metric {
+	if x.schedule.Metric != "" {
+		return x.schedule.Metric
+	}
+	if p, ok := problem.(mli.MetricProblem); ok {
+		return p.Metric()
+	}
+	return mli.MetricAccuracy
+}
+
+// Assess exploits the population on the given number of problem episodes
+// and measures its performance. An episode counts as correct, and as
+// having reached the goal, if it ends with a reward of 1000.
+func (x *Xcs) Assess(problem mli.Problem, ruleSet *Population, samples int) (Assessment, error) {
+	if samples < 1 {
+		return Assessment{}, InvalidParam("samples", samples, "must be at least 1")
+	}
+	stepCap := x.schedule.EvalStepCap
 	numCorrect := 0
-	numIncorrect := 0
-	for j := 0; j < 100; j++ {
+	errorSum := 0.0
+	numPredictions := 0
+	numSteps := 0
+	rewardSum := 0.0
+	for j := 0; j < samples; j++ {
 		problem.Reset()
-		for problem.IsAtEndState() == false {
+		steps := 0
+		correct := false
+		pending := false
+		var lastPrediction, lastReward float64
+		for problem.IsAtEndState() == false && (stepCap == 0 || steps < stepCap) {
 			dataItem := problem.ObtainInput()
-			matchSet := x.ObtainMatchingClassifiers(ruleSet, dataItem)
-			if matchSet.Len() == 0 {
-				numIncorrect += 1
+			action, prediction, err := x.exploit(ruleSet, dataItem)
+			if errors.Is(err, ErrNoMatch) {
 				break
 			}
-			predictionArray := x.CreatePredictionArray(matchSet)
-			bestAction := 0
-			expP := 0.0
-			for key, expPTmp := range predictionArray {
-				if expPTmp > expP {
-					expP = expPTmp
-					bestAction = key
-				}
+			if err != nil {
+				return Assessment{}, err
+			}
+			if pending {
+				errorSum += math.Abs(lastPrediction - (lastReward + x.params.Gamma*prediction))
+				numPredictions += 1
 			}
-			reward := problem.Effect(bestAction)
+			reward := effect(problem, action)
+			rewardSum += reward
+			steps += 1
+			lastPrediction, lastReward, pending = prediction, reward, true
 			if problem.IsAtEndState() {
-				if reward == 1000 {
-					numCorrect += 1
-				} else {
-					numIncorrect += 1
-				}
+				errorSum += math.Abs(prediction - reward)
+				numPredictions += 1
+				correct = reward == 1000
 			}
 		}
-	}
-	if (numCorrect + numIncorrect) > 0 {
-		propCorrect := (float64(numCorrect) / (float64(numCorrect) + float64(numIncorrect)))
-		fmt.Printf("Post-cycle eval #%v. Proportion correct: %v\n", macroStep, propCorrect)
-	}
+		if correct {
+			numCorrect += 1
+		} else if stepCap > 0 {
+			steps = stepCap
+		}
+		numSteps += steps
+	}
+	a := Assessment{
+		Metric:            x.metric(problem),
+		ProportionCorrect: float64(numCorrect) / float64(samples),
+		AverageSteps:      float64(numSteps) / float64(samples),
+		AverageReward:     rewardSum / float64(samples),
+	}
+	if numPredictions > 0 {
+		a.SystemError = errorSum / float64(numPredictions)
+	}
+	switch a.Metric {
+	case mli.MetricSteps:
+		a.Value = a.AverageSteps
+	case mli.MetricReward:
+		a.Value = a.AverageReward
+	case mli.MetricSystemError:
+		a.Value = a.SystemError
+	default:
+		a.Value = a.ProportionCorrect
+	}
+	return a, nil
 }
 
+// OperateOn trains on the problem, reporting progress and the evolved
+// population to the observer. As mli.Algorithm gives it no way to return
+// an error, a failure is logged and ends the run; use Train to handle
+// errors.
 func (x *Xcs) OperateOn(problem mli.Problem) {
+	if _, err := x.Train(problem); err != nil {
+		log.Print(err)
+	}
+}
 
-	ruleSet := list.New()
+// Train runs the configured number of iterations on the problem and
+// returns the evolved population. It stops at the first error, which
+// includes a failing checkpoint handler.
+func (x *Xcs) Train(problem mli.Problem) (*Population, error) {
+	if err := x.params.Validate(); err != nil {
+		return nil, err
+	}
+	if err := x.schedule.Validate(); err != nil {
+		return nil, err
+	}
+	state := &trainingState{ruleSet: NewPopulation()}
+	if err := x.train(problem, state); err != nil {
+		return nil, err
+	}
+	x.observer.TrainingFinished(TrainingEvent{state.iteration, state.ruleSet})
+	return state.ruleSet, nil
+}
 
-	cumulativeMicroSteps := int64(0)
-	macroStep := 0
-	for i := 0; i < 80001; i++ {
-		microStep := 0
-		problem.Reset()
-		var lastActionSet *list.List
-		var lastReward int
-		for problem.IsAtEndState() == false {
-			dataItem := problem.ObtainInput()
-			matchSet := x.CreateMatchSet(ruleSet, dataItem, cumulativeMicroSteps)
-			predictionArray := x.CreatePredictionArray(matchSet)
-			paRandomIdx := rand.Intn(len(predictionArray))
-			idx := 0
-			bestAction := 0
-			for k := range predictionArray {
-				if idx == paRandomIdx {
-					bestAction = k
-					break
-				}
-				idx++
+func (x *Xcs) train(problem mli.Problem, state *trainingState) error {
+	for state.iteration < x.schedule.Iterations {
+		steps, totalReward, err := x.runEpisode(problem, state)
+		if err != nil {
+			return fmt.Errorf("iteration %v: %w", state.iteration, err)
+		}
+		i := state.iteration
+		x.observer.IterationFinished(IterationEvent{i, steps, totalReward, state.ruleSet.Len()})
+		if x.schedule.EvalInterval > 0 && i != 0 && i%x.schedule.EvalInterval == 0 {
+			if err := x.Evaluate(problem, state.ruleSet, state.macroStep); err != nil {
+				return fmt.Errorf("evaluation after iteration %v: %w", i, err)
 			}
+		}
+		state.macroStep += 1
+		state.iteration += 1
+		if x.schedule.CheckpointInterval > 0 && x.onCheckpoint != nil && state.iteration%x.schedule.CheckpointInterval == 0 {
+			if err := x.onCheckpoint(x.checkpoint(state)); err != nil {
+				return fmt.Errorf("checkpoint after iteration %v: %w", i, err)
+			}
+		}
+	}
+	return nil
+}
+
+// runEpisode runs one training episode and returns the number of steps
+// taken and the total reward received.
+func (x *Xcs) runEpisode(problem mli.Problem, state *trainingState) (int, float64, error) {
+	ruleSet := state.ruleSet
+	cumulativeMicroSteps := state.cumulativeMicroSteps
+	microStep := 0
+	totalReward := 0.0
+	problem.Reset()
+	var lastActionSet []*Classifier
+	var lastDataItem mli.DataItem
+	var lastReward float64
+	for problem.IsAtEndState() == false {
+		dataItem := problem.ObtainInput()
+		matchSet, err := x.CreateMatchSet(ruleSet, dataItem, cumulativeMicroSteps)
+		if err != nil {
+			return 0, 0, err
+		}
+		predictionArray := x.CreatePredictionArray(matchSet, dataItem)
+		// The previous action set is updated towards the best prediction
+		// whether or not this step explores (Q-learning).
+		maxP := predictionArray[bestAction(predictionArray)]
+		actions := sortedActions(predictionArray)
+		action := actions[x.rnd.Intn(len(actions))]
+		if x.rnd.Float64() > x.params.PExplore {
 			expP := 0.0
-			if rand.Float64() > pExplore {
-				for k, val := range predictionArray {
-					if val > expP {
-						expP = val
-						bestAction = k
-					}
+			for _, k := range actions {
+				if predictionArray[k] > expP {
+					expP = predictionArray[k]
+					action = k
 				}
-			} else {
-				expP = predictionArray[bestAction]
 			}
-			actionSet := x.CreateActionSet(matchSet, bestAction)
-			reward := problem.Effect(bestAction)
-			if lastActionSet != nil {
-				capitalP := float64(lastReward) + gamma*expP
-				x.UpdateActionSet(capitalP, lastActionSet, ruleSet)
-				if cumulativeMicroSteps%thetaGa == 0 {
-					x.RunGeneticAlgorithm(lastActionSet, dataItem, ruleSet, cumulativeMicroSteps)
+		}
+		actionSet := x.CreateActionSet(matchSet, action)
+		reward := effect(problem, action)
+		totalReward += reward
+		if lastActionSet != nil {
+			capitalP := lastReward + x.params.Gamma*maxP
+			lastActionSet = x.UpdateActionSet(capitalP, lastActionSet, lastDataItem, ruleSet)
+			if cumulativeMicroSteps%int64(x.params.ThetaGa) == 0 {
+				if err := x.RunGeneticAlgorithm(lastActionSet, lastDataItem, ruleSet, cumulativeMicroSteps); err != nil {
+					return 0, 0, err
 				}
 			}
-			if problem.IsAtEndState() {
-				capitalP := float64(reward)
-				x.UpdateActionSet(capitalP, actionSet, ruleSet)
-				if cumulativeMicroSteps%thetaGa == 0 {
-					x.RunGeneticAlgorithm(actionSet, dataItem, ruleSet, cumulativeMicroSteps)
+		}
+		if problem.IsAtEndState() {
+			// An episode cut off before its end has no final payoff, so
+			// its last action set is left as it is.
+			if !cutOff(problem) {
+				capitalP := reward
+				actionSet = x.UpdateActionSet(capitalP, actionSet, dataItem, ruleSet)
+				if cumulativeMicroSteps%int64(x.params.ThetaGa) == 0 {
+					if err := x.RunGeneticAlgorithm(actionSet, dataItem, ruleSet, cumulativeMicroSteps); err != nil {
+						return 0, 0, err
+					}
 				}
-				lastActionSet = nil
-				lastReward = -1.0
-			} else {
-				lastActionSet = actionSet
-				lastReward = reward
 			}
-			microStep += 1
-		}
-		if i != 0 && i%50 == 0 {
-			x.Evaluate(problem, ruleSet, macroStep)
+			lastActionSet = nil
+			lastDataItem = nil
+			lastReward = -1.0
+		} else {
+			lastActionSet = actionSet
+			lastDataItem = dataItem
+			lastReward = reward
 		}
-		macroStep += 1
+		microStep += 1
 		cumulativeMicroSteps += 1
 	}
+	state.cumulativeMicroSteps = cumulativeMicroSteps
+	return microStep, totalReward, nil
+}
+
+// effect performs the action, reading the reward of a
+// mli.RealRewardProblem as a real value.
+func effect(problem mli.Problem, action int) float64 {
+	if p, ok := problem.(mli.RealRewardProblem); ok {
+		return p.RealEffect(action)
+	}
+	return float64(problem.Effect(action))
+}
+
+// cutOff reports whether the episode of a mli.TruncatedProblem was
+// cut off rather than ended.
+func cutOff(problem mli.Problem) bool {
+	p, ok := problem.(mli.TruncatedProblem)
+	return ok && p.Truncated()
+}
+
+// exploit returns the action with the highest system prediction for the
+// data item together with that prediction, or ErrNoMatch if no
+// classifier matches the data item.
+func (x *Xcs) exploit(ruleSet *Population, dataItem mli.DataItem) (int, float64, error) {
+	matchSet, err := x.ObtainMatchingClassifiers(ruleSet, dataItem)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(matchSet) == 0 {
+		return 0, 0, ErrNoMatch
+	}
+	predictionArray := x.CreatePredictionArray(matchSet, dataItem)
+	action := bestAction(predictionArray)
+	return action, predictionArray[action], nil
+}
+
+// bestAction returns the action with the highest prediction, preferring
+// the lowest-numbered action on ties.
+func bestAction(predictionArray map[int]float64) int {
+	actions := sortedActions(predictionArray)
+	best := actions[0]
+	for _, a := range actions[1:] {
+		if predictionArray[a] > predictionArray[best] {
+			best = a
+		}
+	}
+	return best
+}
 
-	for e := ruleSet.Front(); e != nil; e = e.Next() {
-		cl := e.Value.(*Classifier)
-		fmt.Println(cl.ToString())
+// sortedActions returns the actions of a prediction array in ascending
+// order so that action choice does not depend on map iteration order.
+func sortedActions(predictionArray map[int]float64) []int {
+	actions := make([]int, 0, len(predictionArray))
+	for a := range predictionArray {
+		actions = append(actions, a)
 	}
+	sort.Ints(actions)
+	return actions
 }
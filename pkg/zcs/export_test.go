@@ -0,0 +1,8 @@
+package zcs
+
+import "github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+
+// SelectParent exposes selectParent to the tests.
+func (z *Zcs) SelectParent(classifiers []*xcs.Classifier) *xcs.Classifier {
+	return z.selectParent(classifiers)
+}
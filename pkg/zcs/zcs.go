@@ -0,0 +1,369 @@
+// Package zcs provides ZCS, the zeroth-level classifier system of Wilson
+// (1994). Where XCS bases fitness on the accuracy of a classifier's
+// prediction, ZCS bases it on strength, the payoff the classifier has
+// accumulated: actions are chosen by roulette wheel on the strength of
+// their advocates, strength is passed back along the chain of action
+// sets by a bucket brigade, and a panmictic genetic algorithm breeds
+// strong classifiers from the whole population.
+//
+// ZCS reuses the classifiers, conditions, population, crossover and
+// mutation of package xcs, storing strength as a classifier's fitness,
+// so that its populations can be saved and inspected like any other.
+// Each classifier has a numerosity of 1. The population starts empty and
+// is filled by covering.
+package zcs
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/mli"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+)
+
+// Params holds the settings of ZCS that XCS does not have. The rest,
+// among them the population size, learning rate, discount, crossover
+// and mutation rates, come from xcs.Params.
+type Params struct {
+	// Tau is the tax taken from the strength of the classifiers in the
+	// match set but not in the action set.
+	Tau float64 `json:"tau" yaml:"tau" toml:"tau"`
+	// Rho is the probability that the genetic algorithm runs on a step.
+	Rho float64 `json:"rho" yaml:"rho" toml:"rho"`
+	// Phi triggers covering when the strength of the match set is below
+	// Phi times the mean strength of the population.
+	Phi float64 `json:"phi" yaml:"phi" toml:"phi"`
+	// InitialStrength is the strength of the classifiers covering an
+	// empty population; later ones get the mean strength.
+	InitialStrength float64 `json:"initialStrength" yaml:"initialStrength" toml:"initialStrength"`
+}
+
+// DefaultParams returns the values of Wilson (1994).
+func DefaultParams() Params {
+	return Params{
+		Tau:             0.1,
+		Rho:             0.25,
+		Phi:             0.5,
+		InitialStrength: 20,
+	}
+}
+
+func (p Params) Validate() error {
+	switch {
+	case p.Tau < 0 || p.Tau >= 1:
+		return xcs.InvalidParam("tau", p.Tau, "must be in [0, 1)")
+	case p.Rho < 0 || p.Rho > 1:
+		return xcs.InvalidParam("rho", p.Rho, "must be in [0, 1]")
+	case p.Phi < 0:
+		return xcs.InvalidParam("phi", p.Phi, "must not be negative")
+	case p.InitialStrength <= 0:
+		return xcs.InvalidParam("initialStrength", p.InitialStrength, "must be greater than 0")
+	}
+	return nil
+}
+
+type Zcs struct {
+	x         *xcs.Xcs
+	params    xcs.Params
+	zcsParams Params
+	schedule  xcs.Schedule
+	rnd       *rng.Rand
+	observer  xcs.Observer
+}
+
+// New returns a ZCS that draws all of its random numbers from rnd. The
+// genetic algorithm of ZCS breeds from the whole population rather than
+// a niche, so offspring always use free mutation, as in Wilson (1994),
+// whatever params.Mutation names.
+func New(params xcs.Params, zcsParams Params, rnd *rng.Rand) (*Zcs, error) {
+	if err := zcsParams.Validate(); err != nil {
+		return nil, err
+	}
+	params.Mutation = xcs.MutationFree
+	x, err := xcs.New(params, rnd)
+	if err != nil {
+		return nil, err
+	}
+	x.SetPredictionFunc(Strengths)
+	z := &Zcs{x: x, params: params, zcsParams: zcsParams, schedule: xcs.DefaultSchedule(), rnd: rnd}
+	z.SetObserver(&xcs.ConsoleObserver{Writer: os.Stdout, PrintPopulation: true})
+	return z, nil
+}
+
+// SetObserver replaces the observer notified of run events; nil
+// silences the run.
+func (z *Zcs) SetObserver(observer xcs.Observer) {
+	if observer == nil {
+		observer = xcs.NopObserver{}
+	}
+	z.observer = observer
+	z.x.SetObserver(observer)
+}
+
+func (z *Zcs) GetParams() xcs.Params {
+	return z.params
+}
+
+func (z *Zcs) GetZcsParams() Params {
+	return z.zcsParams
+}
+
+func (z *Zcs) SetSchedule(schedule xcs.Schedule) error {
+	if err := z.x.SetSchedule(schedule); err != nil {
+		return err
+	}
+	z.schedule = schedule
+	return nil
+}
+
+// Assess evaluates the population, choosing in every state the action
+// whose advocates are strongest; see xcs.Xcs.Assess.
+func (z *Zcs) Assess(problem mli.Problem, ruleSet *xcs.Population, samples int) (xcs.Assessment, error) {
+	return z.x.Assess(problem, ruleSet, samples)
+}
+
+// OperateOn trains on the problem; see xcs.Xcs.OperateOn.
+func (z *Zcs) OperateOn(problem mli.Problem) {
+	if _, err := z.Train(problem); err != nil {
+		log.Print(err)
+	}
+}
+
+// Train runs the configured number of episodes on the problem and
+// returns the evolved population.
+func (z *Zcs) Train(problem mli.Problem) (*xcs.Population, error) {
+	ruleSet := xcs.NewPopulation()
+	step := int64(0)
+	for i := 0; i < z.schedule.Iterations; i++ {
+		steps, reward, err := z.runEpisode(problem, ruleSet, &step)
+		if err != nil {
+			return nil, fmt.Errorf("iteration %v: %w", i, err)
+		}
+		z.observer.IterationFinished(xcs.IterationEvent{Iteration: i, Steps: steps, TotalReward: reward, MacroClassifiers: ruleSet.Len()})
+		if z.schedule.EvalInterval > 0 && i != 0 && i%z.schedule.EvalInterval == 0 {
+			if err := z.x.Evaluate(problem, ruleSet, i); err != nil {
+				return nil, fmt.Errorf("evaluation after iteration %v: %w", i, err)
+			}
+		}
+	}
+	z.observer.TrainingFinished(xcs.TrainingEvent{Iterations: z.schedule.Iterations, Population: ruleSet})
+	return ruleSet, nil
+}
+
+func (z *Zcs) runEpisode(problem mli.Problem, ruleSet *xcs.Population, step *int64) (int, float64, error) {
+	problem.Reset()
+	var lastActionSet []*xcs.Classifier
+	steps := 0
+	totalReward := 0.0
+	for !problem.IsAtEndState() {
+		dataItem := problem.ObtainInput()
+		matchSet, err := z.x.ObtainMatchingClassifiers(ruleSet, dataItem)
+		if err != nil {
+			return 0, 0, err
+		}
+		if z.needsCovering(matchSet, ruleSet) {
+			cl, err := z.cover(dataItem, ruleSet, *step)
+			if err != nil {
+				return 0, 0, err
+			}
+			matchSet = append(matchSet, cl)
+		}
+		action := z.selectAction(matchSet)
+		actionSet := z.x.CreateActionSet(matchSet, action)
+		reward := float64(problem.Effect(action))
+		z.Reinforce(matchSet, actionSet, lastActionSet, reward, ruleSet)
+		if z.rnd.Float64() < z.zcsParams.Rho {
+			if err := z.RunGeneticAlgorithm(dataItem, ruleSet, *step); err != nil {
+				return 0, 0, err
+			}
+		}
+		lastActionSet = actionSet
+		totalReward += reward
+		steps++
+		*step++
+	}
+	return steps, totalReward, nil
+}
+
+// needsCovering reports whether the match set is empty or weaker than
+// Phi times the mean strength of the population.
+func (z *Zcs) needsCovering(matchSet []*xcs.Classifier, ruleSet *xcs.Population) bool {
+	if len(matchSet) == 0 {
+		return true
+	}
+	strength := 0.0
+	for _, cl := range matchSet {
+		strength += cl.GetFitness()
+	}
+	return strength < z.zcsParams.Phi*ruleSet.FitnessSum()/float64(ruleSet.Len())
+}
+
+// cover adds a classifier with a random action matching the data item,
+// with the mean strength of the population, and deletes one if the
+// population is full.
+func (z *Zcs) cover(dataItem mli.DataItem, ruleSet *xcs.Population, step int64) (*xcs.Classifier, error) {
+	cl, err := z.x.CoverAction(dataItem, z.rnd.Intn(z.params.MaxAction+1), step)
+	if err != nil {
+		return nil, err
+	}
+	cl.SetFitness(z.meanStrength(ruleSet))
+	z.insert(cl, ruleSet)
+	z.observer.CoveringOccurred(xcs.CoveringEvent{Step: step, Classifier: cl})
+	return cl, nil
+}
+
+func (z *Zcs) meanStrength(ruleSet *xcs.Population) float64 {
+	if ruleSet.Len() == 0 {
+		return z.zcsParams.InitialStrength
+	}
+	return ruleSet.FitnessSum() / float64(ruleSet.Len())
+}
+
+// selectAction chooses an action by roulette wheel, in proportion to
+// the total strength of the classifiers advocating it.
+func (z *Zcs) selectAction(matchSet []*xcs.Classifier) int {
+	total := 0.0
+	for _, cl := range matchSet {
+		total += cl.GetFitness()
+	}
+	choicePoint := z.rnd.Float64() * total
+	sum := 0.0
+	for _, cl := range matchSet {
+		sum += cl.GetFitness()
+		if sum > choicePoint {
+			return cl.GetAction()
+		}
+	}
+	return matchSet[len(matchSet)-1].GetAction()
+}
+
+// Reinforce applies the bucket brigade: the action set pays Beta of its
+// strength into a bucket, of which Gamma is shared by the previous action
+// set; the action set shares Beta of the reward; and the classifiers of
+// the match set that did not act are taxed Tau of their strength.
+func (z *Zcs) Reinforce(matchSet, actionSet, lastActionSet []*xcs.Classifier, reward float64, ruleSet *xcs.Population) {
+	beta := z.params.Beta
+	bucket := 0.0
+	inActionSet := make(map[*xcs.Classifier]bool, len(actionSet))
+	for _, cl := range actionSet {
+		inActionSet[cl] = true
+		bucket += beta * cl.GetFitness()
+		ruleSet.SetFitness(cl, (1-beta)*cl.GetFitness()+beta*reward/float64(len(actionSet)))
+	}
+	for _, cl := range lastActionSet {
+		if ruleSet.Contains(cl) {
+			ruleSet.SetFitness(cl, cl.GetFitness()+z.params.Gamma*bucket/float64(len(lastActionSet)))
+		}
+	}
+	for _, cl := range matchSet {
+		if !inActionSet[cl] {
+			ruleSet.SetFitness(cl, (1-z.zcsParams.Tau)*cl.GetFitness())
+		}
+	}
+}
+
+// RunGeneticAlgorithm breeds two classifiers from parents chosen from
+// the whole population by roulette wheel on strength. Each parent gives
+// half of its strength to its child; children that cross over share
+// their strength equally.
+func (z *Zcs) RunGeneticAlgorithm(dataItem mli.DataItem, ruleSet *xcs.Population, step int64) error {
+	if ruleSet.Len() == 0 {
+		return nil
+	}
+	parent1 := z.selectParent(ruleSet.Classifiers())
+	parent2 := z.selectParent(ruleSet.Classifiers())
+	child1 := parent1.GetOffspring()
+	child2 := parent2.GetOffspring()
+	for _, pair := range [][2]*xcs.Classifier{{parent1, child1}, {parent2, child2}} {
+		parent, child := pair[0], pair[1]
+		child.SetFitness(parent.GetFitness() / 2)
+		ruleSet.SetFitness(parent, parent.GetFitness()/2)
+		child.SetTimeStamp(step)
+	}
+	if z.rnd.Float64() < z.params.Chi {
+		z.x.ApplyCrossover(child1, child2)
+	}
+	for _, child := range []*xcs.Classifier{child1, child2} {
+		if err := z.x.ApplyMutation(child, dataItem); err != nil {
+			return err
+		}
+		z.insert(child, ruleSet)
+	}
+	z.observer.GeneticAlgorithmInvoked(xcs.GaEvent{Step: step, ActionSetSize: ruleSet.Len(), Parent1: parent1, Parent2: parent2, Child1: child1, Child2: child2})
+	return nil
+}
+
+// selectParent chooses a classifier by roulette wheel on strength. ZCS
+// keeps to the roulette wheel whatever selection Params.Selection names
+// for XCS.
+func (z *Zcs) selectParent(classifiers []*xcs.Classifier) *xcs.Classifier {
+	if len(classifiers) == 1 {
+		return classifiers[0]
+	}
+	total := 0.0
+	for _, cl := range classifiers {
+		total += cl.GetFitness()
+	}
+	choicePoint := z.rnd.Float64() * total
+	sum := 0.0
+	for _, cl := range classifiers {
+		sum += cl.GetFitness()
+		if sum > choicePoint {
+			return cl
+		}
+	}
+	return classifiers[z.rnd.Intn(len(classifiers))]
+}
+
+// insert adds the classifier to the population and, while the population
+// is over MaxPop, deletes classifiers chosen by roulette wheel in
+// inverse proportion to strength.
+func (z *Zcs) insert(cl *xcs.Classifier, ruleSet *xcs.Population) {
+	ruleSet.Add(cl)
+	for ruleSet.Len() > z.params.MaxPop {
+		z.delete(ruleSet)
+	}
+}
+
+func (z *Zcs) delete(ruleSet *xcs.Population) {
+	classifiers := ruleSet.Classifiers()
+	total := 0.0
+	for _, cl := range classifiers {
+		total += deletionWeight(cl)
+	}
+	choicePoint := z.rnd.Float64() * total
+	victim := classifiers[len(classifiers)-1]
+	sum := 0.0
+	for _, cl := range classifiers {
+		sum += deletionWeight(cl)
+		if sum > choicePoint {
+			victim = cl
+			break
+		}
+	}
+	ruleSet.Remove(victim)
+	z.observer.ClassifierDeleted(xcs.DeletionEvent{Classifier: victim, Removed: true})
+}
+
+// minStrength bounds the strengths used for deletion away from 0, so
+// that classifiers taxed down to nothing are as likely to be deleted as
+// one another rather than giving an infinite weight.
+const minStrength = 1e-6
+
+func deletionWeight(cl *xcs.Classifier) float64 {
+	return 1 / math.Max(cl.GetFitness(), minStrength)
+}
+
+// Strengths returns the total strength of the classifiers advocating
+// each action in the match set. It is the prediction array of ZCS, so
+// that exploitation chooses the strongest action.
+func Strengths(matchSet []*xcs.Classifier, dataItem mli.DataItem) map[int]float64 {
+	strengths := make(map[int]float64)
+	for _, cl := range matchSet {
+		strengths[cl.GetAction()] += cl.GetFitness()
+	}
+	return strengths
+}
@@ -0,0 +1,106 @@
+package zcs_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/multiplexer"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/rng"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/xcs"
+	"github.com/matthewrkarlsen/xcs-in-go/pkg/zcs"
+)
+
+// train runs ZCS on the 6-bit multiplexer and returns the proportion of
+// inputs it then answers correctly.
+func train(t *testing.T, params xcs.Params, iterations int) float64 {
+	t.Helper()
+	rnd := rng.New(1)
+	problem, err := multiplexer.New(6, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z, err := zcs.New(params, zcs.DefaultParams(), rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z.SetObserver(xcs.NopObserver{})
+	if err := z.SetSchedule(xcs.Schedule{Iterations: iterations}); err != nil {
+		t.Fatal(err)
+	}
+	ruleSet, err := z.Train(problem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cl := range ruleSet.Classifiers() {
+		if cl.GetFitness() < 0 {
+			t.Errorf("negative strength: %v", cl.ToString())
+		}
+	}
+	a, err := z.Assess(problem, ruleSet, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a.ProportionCorrect
+}
+
+// zcsParams returns the parameters of configs/multiplexer6-zcs.yaml.
+func zcsParams() xcs.Params {
+	params := xcs.DefaultParams()
+	params.MaxPop = 400
+	params.Chi = 0.5
+	params.Mu = 0.01
+	params.MuAction = 0.01
+	return params
+}
+
+func TestLearnsMultiplexer(t *testing.T) {
+	if correct := train(t, zcsParams(), 20000); correct < 0.8 {
+		t.Errorf("proportion correct after 20000 iterations = %v, want at least 0.8", correct)
+	}
+}
+
+// TestSelectParent checks that parents are chosen by roulette wheel on
+// strength even when the parameters name tournament selection.
+func TestSelectParent(t *testing.T) {
+	params := zcsParams()
+	params.Selection = xcs.SelectionTournament
+	z, err := zcs.New(params, zcs.DefaultParams(), rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	strengths := []float64{10, 30, 60}
+	var classifiers []*xcs.Classifier
+	for _, strength := range strengths {
+		classifiers = append(classifiers, &xcs.Classifier{Fitness: strength, Numerosity: 1})
+	}
+	const draws = 20000
+	counts := make(map[*xcs.Classifier]int)
+	for i := 0; i < draws; i++ {
+		counts[z.SelectParent(classifiers)]++
+	}
+	for i, cl := range classifiers {
+		share, want := float64(counts[cl])/draws, strengths[i]/100
+		if math.Abs(share-want) > 0.015 {
+			t.Errorf("classifier of strength %v chosen %.3f of the time, want %.3f", strengths[i], share, want)
+		}
+	}
+}
+
+func TestUsesFreeMutation(t *testing.T) {
+	z, err := zcs.New(zcsParams(), zcs.DefaultParams(), rng.New(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mutation := z.GetParams().Mutation; mutation != xcs.MutationFree {
+		t.Errorf("mutation = %q, want %q", mutation, xcs.MutationFree)
+	}
+}
+
+func TestRejectsInvalidParams(t *testing.T) {
+	p := zcs.DefaultParams()
+	p.Tau = 1
+	if _, err := zcs.New(zcsParams(), p, rng.New(1)); !errors.Is(err, xcs.ErrInvalidParam) {
+		t.Errorf("New with tau = 1 returned %v", err)
+	}
+}